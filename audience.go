@@ -0,0 +1,19 @@
+package oauth2
+
+// WithinAudience check whether each of the requested resources or audience values is one of the granted values,
+// e.g. the resources requested by the client within its allowed resources
+func WithinAudience(granted, requested []string) bool {
+	for _, v := range requested {
+		found := false
+		for _, gv := range granted {
+			if gv == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,18 @@
+package oauth2_test
+
+import (
+	"testing"
+
+	"github.com/go-oauth2/oauth2/v4"
+)
+
+func TestWithinAudience(t *testing.T) {
+	granted := []string{"https://billing.example.com", "https://admin.example.com"}
+
+	if !oauth2.WithinAudience(granted, []string{"https://billing.example.com"}) || !oauth2.WithinAudience(granted, nil) {
+		t.Fatal("requested audience not within the granted audience")
+	}
+	if oauth2.WithinAudience(granted, []string{"https://billing.example.com", "https://other.example.com"}) {
+		t.Fatal("unexpected audience within the granted audience")
+	}
+}
@@ -0,0 +1,105 @@
+package oauth2
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// AuthorizationDetail the authorization details object of a rich authorization request (RFC 9396 section 2),
+// the common fields and the fields specific to its type are kept as the decoded json object
+type AuthorizationDetail map[string]interface{}
+
+// GetType the type of the authorization details object
+func (d AuthorizationDetail) GetType() string {
+	v, _ := d["type"].(string)
+	return v
+}
+
+// Decode decode the authorization details object into the value of its type
+func (d AuthorizationDetail) Decode(v interface{}) error {
+	buf, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, v)
+}
+
+// Equal check whether the authorization details objects have the same fields,
+// the objects are compared by their json encoding so the stored objects match the requested ones
+func (d AuthorizationDetail) Equal(o AuthorizationDetail) bool {
+	dv, err := json.Marshal(d)
+	if err != nil {
+		return false
+	}
+	ov, err := json.Marshal(o)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(dv, ov)
+}
+
+// AuthorizationDetails the authorization details objects of the authorization_details parameter
+type AuthorizationDetails []AuthorizationDetail
+
+// ParseAuthorizationDetails parse the json array of the authorization_details parameter,
+// errors.ErrInvalidAuthorizationDetails is returned for the malformed array or the object without type
+func ParseAuthorizationDetails(s string) (AuthorizationDetails, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var details AuthorizationDetails
+	if err := json.Unmarshal([]byte(s), &details); err != nil {
+		return nil, errors.ErrInvalidAuthorizationDetails
+	}
+	for _, d := range details {
+		if d.GetType() == "" {
+			return nil, errors.ErrInvalidAuthorizationDetails
+		}
+	}
+	return details, nil
+}
+
+// String the json array of the authorization details
+func (d AuthorizationDetails) String() string {
+	if len(d) == 0 {
+		return ""
+	}
+	buf, err := json.Marshal(d)
+	if err != nil {
+		return ""
+	}
+	return string(buf)
+}
+
+// Types the types of the authorization details, the duplicates are removed
+func (d AuthorizationDetails) Types() []string {
+	var types []string
+	seen := make(map[string]bool)
+	for _, v := range d {
+		if t := v.GetType(); !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// Contains check whether each of the authorization details objects of o is one of d
+func (d AuthorizationDetails) Contains(o AuthorizationDetails) bool {
+	for _, ov := range o {
+		found := false
+		for _, v := range d {
+			if v.Equal(ov) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
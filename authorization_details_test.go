@@ -0,0 +1,49 @@
+package oauth2_test
+
+import (
+	"testing"
+
+	"github.com/go-oauth2/oauth2/v4"
+)
+
+func TestParseAuthorizationDetails(t *testing.T) {
+	details, err := oauth2.ParseAuthorizationDetails(`[{"type":"payment_initiation","instructedAmount":{"currency":"EUR","amount":"100.00"}}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(details) != 1 || details[0].GetType() != "payment_initiation" {
+		t.Fatalf("unexpected authorization details: %v", details)
+	}
+
+	var payment struct {
+		InstructedAmount struct {
+			Currency string `json:"currency"`
+			Amount   string `json:"amount"`
+		} `json:"instructedAmount"`
+	}
+	if err := details[0].Decode(&payment); err != nil || payment.InstructedAmount.Amount != "100.00" {
+		t.Fatalf("unexpected payment: %v %v", payment, err)
+	}
+
+	for _, v := range []string{`{"type":"payment_initiation"}`, `[{"actions":["read"]}]`, `[{"type":""}]`, `[1]`} {
+		if _, err := oauth2.ParseAuthorizationDetails(v); err == nil {
+			t.Fatalf("malformed authorization details accepted: %s", v)
+		}
+	}
+}
+
+func TestAuthorizationDetailsContains(t *testing.T) {
+	granted, _ := oauth2.ParseAuthorizationDetails(`[{"type":"a","actions":["read"]},{"type":"b","identifier":"x"}]`)
+	same, _ := oauth2.ParseAuthorizationDetails(`[{"identifier":"x","type":"b"}]`)
+	other, _ := oauth2.ParseAuthorizationDetails(`[{"type":"a","actions":["read","write"]}]`)
+
+	if !granted.Contains(same) {
+		t.Fatal("granted authorization details not contained")
+	}
+	if granted.Contains(other) {
+		t.Fatal("unexpected authorization details contained")
+	}
+	if v := granted.Types(); len(v) != 2 {
+		t.Fatalf("unexpected types: %v", v)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"strings"
+	"sync"
 )
 
 // ResponseType the type of authorization request
@@ -28,6 +29,9 @@ const (
 	PasswordCredentials GrantType = "password"
 	ClientCredentials   GrantType = "client_credentials"
 	Refreshing          GrantType = "refresh_token"
+	DeviceCode          GrantType = "urn:ietf:params:oauth:grant-type:device_code"
+	TokenExchange       GrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	JWTBearer           GrantType = "urn:ietf:params:oauth:grant-type:jwt-bearer"
 	Implicit            GrantType = "__implicit"
 )
 
@@ -35,12 +39,55 @@ func (gt GrantType) String() string {
 	if gt == AuthorizationCode ||
 		gt == PasswordCredentials ||
 		gt == ClientCredentials ||
-		gt == Refreshing {
+		gt == Refreshing ||
+		gt == DeviceCode ||
+		gt == TokenExchange ||
+		gt == JWTBearer {
+		return string(gt)
+	}
+
+	grantTypesMu.RLock()
+	defer grantTypesMu.RUnlock()
+	if grantTypes[gt] {
 		return string(gt)
 	}
 	return ""
 }
 
+// the registered custom grant types
+var (
+	grantTypesMu sync.RWMutex
+	grantTypes   = make(map[GrantType]bool)
+)
+
+// RegisterGrantType register the custom grant type, so that it is accepted as a valid grant type
+func RegisterGrantType(gt GrantType) {
+	grantTypesMu.Lock()
+	defer grantTypesMu.Unlock()
+	grantTypes[gt] = true
+}
+
+// define the token type identifiers of the token exchange (RFC 8693 section 3)
+const (
+	AccessTokenType  = "urn:ietf:params:oauth:token-type:access_token"
+	RefreshTokenType = "urn:ietf:params:oauth:token-type:refresh_token"
+	IDTokenType      = "urn:ietf:params:oauth:token-type:id_token"
+	JWTTokenType     = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// RequestURIPrefix the prefix of the request uri of the pushed authorization request (RFC 9126 section 2.2)
+const RequestURIPrefix = "urn:ietf:params:oauth:request_uri:"
+
+// DeviceCodeStatus the status of the device authorization
+type DeviceCodeStatus string
+
+// define the status of the device authorization
+const (
+	DeviceCodePending  DeviceCodeStatus = "pending"
+	DeviceCodeApproved DeviceCodeStatus = "approved"
+	DeviceCodeDenied   DeviceCodeStatus = "denied"
+)
+
 // CodeChallengeMethod PCKE method
 type CodeChallengeMethod string
 
@@ -74,3 +121,24 @@ func (ccm CodeChallengeMethod) Validate(cc, ver string) bool {
 		return false
 	}
 }
+
+// ClientAuthMethod the client authentication method of the token endpoint (RFC 7591 section 2)
+type ClientAuthMethod string
+
+// define the client authentication methods
+const (
+	ClientSecretBasic       ClientAuthMethod = "client_secret_basic"
+	ClientSecretPost        ClientAuthMethod = "client_secret_post"
+	ClientSecretJWT         ClientAuthMethod = "client_secret_jwt"
+	PrivateKeyJWT           ClientAuthMethod = "private_key_jwt"
+	TLSClientAuth           ClientAuthMethod = "tls_client_auth"
+	SelfSignedTLSClientAuth ClientAuthMethod = "self_signed_tls_client_auth"
+	ClientAuthNone          ClientAuthMethod = "none"
+)
+
+func (cam ClientAuthMethod) String() string {
+	return string(cam)
+}
+
+// ClientAssertionType the client assertion type of the jwt client authentication (RFC 7523 section 2.2)
+const ClientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
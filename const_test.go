@@ -26,3 +26,14 @@ func TestValidateS256NoPadding(t *testing.T) {
 		t.Fatal("not valid")
 	}
 }
+
+func TestRegisterGrantType(t *testing.T) {
+	gt := oauth2.GrantType("urn:example:params:oauth:grant-type:custom")
+	if gt.String() != "" {
+		t.Fatal("unregistered grant type is valid")
+	}
+	oauth2.RegisterGrantType(gt)
+	if gt.String() != string(gt) {
+		t.Fatal("registered grant type is not valid")
+	}
+}
@@ -16,4 +16,10 @@ var (
 	ErrMissingCodeVerifier  = errors.New("missing code verifier")
 	ErrMissingCodeChallenge = errors.New("missing code challenge")
 	ErrInvalidCodeChallenge = errors.New("invalid code challenge")
+	ErrInvalidDeviceCode    = errors.New("invalid device code")
+	ErrInvalidUserCode      = errors.New("invalid user code")
+	ErrDuplicateUserCode    = errors.New("duplicate user code")
+	ErrInvalidPushedRequest = errors.New("invalid pushed authorization request")
+	ErrInvalidConsent       = errors.New("invalid consent")
+	ErrReadOnlyClientStore  = errors.New("client store isn't writable")
 )
@@ -49,6 +49,51 @@ var (
 	ErrInvalidCodeChallengeLen        = errors.New("invalid_request")
 )
 
+// https://tools.ietf.org/html/rfc6750#section-3.1
+var (
+	ErrInsufficientScope = errors.New("insufficient_scope")
+)
+
+// https://tools.ietf.org/html/rfc7009#section-2.2.1
+var (
+	ErrUnsupportedTokenType = errors.New("unsupported_token_type")
+)
+
+// https://tools.ietf.org/html/rfc9101#section-6.3
+var (
+	ErrInvalidRequestURI      = errors.New("invalid_request_uri")
+	ErrInvalidRequestObject   = errors.New("invalid_request_object")
+	ErrRequestURINotSupported = errors.New("request_uri_not_supported")
+)
+
+// https://tools.ietf.org/html/rfc8693#section-2.2.2
+var (
+	ErrInvalidTarget = errors.New("invalid_target")
+)
+
+// https://tools.ietf.org/html/rfc9396#section-5
+var (
+	ErrInvalidAuthorizationDetails = errors.New("invalid_authorization_details")
+)
+
+// https://tools.ietf.org/html/rfc9449#section-12.2
+var (
+	ErrInvalidDPoPProof = errors.New("invalid_dpop_proof")
+)
+
+// https://tools.ietf.org/html/rfc7591#section-3.2.2
+var (
+	ErrInvalidClientRedirectURI = errors.New("invalid_redirect_uri")
+	ErrInvalidClientMetadata    = errors.New("invalid_client_metadata")
+)
+
+// https://tools.ietf.org/html/rfc8628#section-3.5
+var (
+	ErrAuthorizationPending = errors.New("authorization_pending")
+	ErrSlowDown             = errors.New("slow_down")
+	ErrExpiredToken         = errors.New("expired_token")
+)
+
 // Descriptions error description
 var Descriptions = map[error]string{
 	ErrInvalidRequest:                 "The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed",
@@ -64,6 +109,19 @@ var Descriptions = map[error]string{
 	ErrCodeChallengeRquired:           "PKCE is required. code_challenge is missing",
 	ErrUnsupportedCodeChallengeMethod: "Selected code_challenge_method not supported",
 	ErrInvalidCodeChallengeLen:        "Code challenge length must be between 43 and 128 charachters long",
+	ErrInsufficientScope:              "The request requires higher privileges than provided by the access token",
+	ErrUnsupportedTokenType:           "The authorization server does not support the revocation of the presented token type",
+	ErrInvalidRequestURI:              "The request_uri in the authorization request returns an error or contains invalid data",
+	ErrInvalidRequestObject:           "The request parameter contains an invalid request object",
+	ErrRequestURINotSupported:         "The authorization server does not support use of the request_uri parameter",
+	ErrInvalidTarget:                  "The requested resource or audience is invalid, unknown, or malformed",
+	ErrInvalidAuthorizationDetails:    "The authorization details are invalid, unknown, or malformed",
+	ErrInvalidDPoPProof:               "The DPoP proof is invalid",
+	ErrInvalidClientRedirectURI:       "The value of one or more redirection URIs is invalid",
+	ErrInvalidClientMetadata:          "The value of one of the client metadata fields is invalid",
+	ErrAuthorizationPending:           "The authorization request is still pending as the end user hasn't yet completed the user-interaction steps",
+	ErrSlowDown:                       "The authorization request is still pending and polling should continue, but the interval must be increased by 5 seconds",
+	ErrExpiredToken:                   "The device code has expired, and the device authorization session has concluded",
 }
 
 // StatusCodes response error HTTP status code
@@ -81,4 +139,17 @@ var StatusCodes = map[error]int{
 	ErrCodeChallengeRquired:           400,
 	ErrUnsupportedCodeChallengeMethod: 400,
 	ErrInvalidCodeChallengeLen:        400,
+	ErrInsufficientScope:              403,
+	ErrUnsupportedTokenType:           400,
+	ErrInvalidRequestURI:              400,
+	ErrInvalidRequestObject:           400,
+	ErrRequestURINotSupported:         400,
+	ErrInvalidTarget:                  400,
+	ErrInvalidAuthorizationDetails:    400,
+	ErrInvalidDPoPProof:               400,
+	ErrInvalidClientRedirectURI:       400,
+	ErrInvalidClientMetadata:          400,
+	ErrAuthorizationPending:           400,
+	ErrSlowDown:                       400,
+	ErrExpiredToken:                   400,
 }
@@ -35,7 +35,7 @@ func init() {
 	flag.BoolVar(&dumpvar, "d", true, "Dump requests and responses")
 	flag.StringVar(&idvar, "i", "222222", "The client id being passed in")
 	flag.StringVar(&secretvar, "s", "22222222", "The client secret being passed in")
-	flag.StringVar(&domainvar, "r", "http://localhost:9094", "The domain of the redirect url")
+	flag.StringVar(&domainvar, "r", "http://localhost:9094/oauth2", "The redirect url of the client")
 	flag.IntVar(&portvar, "p", 9096, "the base port for the server")
 }
 
@@ -62,7 +62,17 @@ func main() {
 	})
 	manager.MapClientStorage(clientStore)
 
-	srv := server.NewServer(server.NewConfig(), manager)
+	// the consent of the user is remembered, the consent page is only shown for the new scopes
+	manager.MustConsentStorage(store.NewMemoryConsentStore())
+
+	cfg := server.NewConfig()
+	cfg.Issuer = fmt.Sprintf("http://localhost:%d", portvar)
+	cfg.AuthorizeEndpoint = cfg.Issuer + "/oauth/authorize"
+	cfg.TokenEndpoint = cfg.Issuer + "/oauth/token"
+	cfg.RevocationEndpoint = cfg.Issuer + "/oauth/revoke"
+	cfg.IntrospectionEndpoint = cfg.Issuer + "/oauth/introspect"
+	cfg.JWKSURI = cfg.Issuer + server.JWKSPath
+	srv := server.NewServer(cfg, manager)
 
 	srv.SetPasswordAuthorizationHandler(func(ctx context.Context, clientID, username, password string) (userID string, err error) {
 		if username == "test" && password == "test" {
@@ -74,6 +84,7 @@ func main() {
 	})
 
 	srv.SetUserAuthorizationHandler(userAuthorizeHandler)
+	srv.SetUserConsentHandler(userConsentHandler)
 
 	srv.SetInternalErrorHandler(func(err error) (re *errors.Response) {
 		log.Println("Internal Error:", err.Error())
@@ -87,6 +98,47 @@ func main() {
 	http.HandleFunc("/login", loginHandler)
 	http.HandleFunc("/auth", authHandler)
 
+	// list and withdraw the consents of the logged in user
+	http.HandleFunc("/consents", func(w http.ResponseWriter, r *http.Request) {
+		store, err := session.Start(r.Context(), w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		uid, ok := store.Get("LoggedInUserID")
+		if !ok {
+			http.Error(w, "Not logged in", http.StatusUnauthorized)
+			return
+		}
+		userID := uid.(string)
+
+		if r.Method == "DELETE" {
+			if err := manager.RevokeConsent(r.Context(), userID, r.FormValue("client_id")); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		consents, err := manager.ListConsents(r.Context(), userID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		data := []map[string]interface{}{}
+		for _, ci := range consents {
+			data = append(data, map[string]interface{}{
+				"client_id":  ci.GetClientID(),
+				"scope":      ci.GetScope(),
+				"granted_at": ci.GetCreateAt().Unix(),
+			})
+		}
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		e.Encode(data)
+	})
+
 	http.HandleFunc("/oauth/authorize", func(w http.ResponseWriter, r *http.Request) {
 		if dumpvar {
 			dumpRequest(os.Stdout, "authorize", r)
@@ -124,6 +176,42 @@ func main() {
 		}
 	})
 
+	http.HandleFunc("/oauth/revoke", func(w http.ResponseWriter, r *http.Request) {
+		if dumpvar {
+			_ = dumpRequest(os.Stdout, "revoke", r) // Ignore the error
+		}
+
+		err := srv.HandleRevocationRequest(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+
+	http.HandleFunc("/oauth/introspect", func(w http.ResponseWriter, r *http.Request) {
+		if dumpvar {
+			_ = dumpRequest(os.Stdout, "introspect", r) // Ignore the error
+		}
+
+		err := srv.HandleIntrospectionRequest(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+
+	http.HandleFunc(server.MetadataPath, func(w http.ResponseWriter, r *http.Request) {
+		err := srv.HandleMetadataRequest(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+
+	http.HandleFunc(server.JWKSPath, func(w http.ResponseWriter, r *http.Request) {
+		err := srv.HandleJWKSRequest(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+
 	http.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
 		if dumpvar {
 			_ = dumpRequest(os.Stdout, "test", r) // Ignore the error
@@ -182,9 +270,41 @@ func userAuthorizeHandler(w http.ResponseWriter, r *http.Request) (userID string
 		return
 	}
 
+	// the login isn't single-use anymore, it's kept for the session so the consent page
+	// and the consents of the user can use it, the user logs in again when the session ends
 	userID = uid.(string)
-	store.Delete("LoggedInUserID")
+	return
+}
+
+func userConsentHandler(w http.ResponseWriter, r *http.Request, clientID, scope string) (consented bool, err error) {
+	if dumpvar {
+		_ = dumpRequest(os.Stdout, "userConsentHandler", r) // Ignore the error
+	}
+	store, err := session.Start(r.Context(), w, r)
+	if err != nil {
+		return
+	}
+
+	consent, ok := store.Get("Consent")
+	if !ok {
+		if r.Form == nil {
+			r.ParseForm()
+		}
+		store.Set("ReturnUri", r.Form)
+		store.Save()
+
+		w.Header().Set("Location", "/auth")
+		w.WriteHeader(http.StatusFound)
+		return
+	}
+
+	store.Delete("Consent")
 	store.Save()
+	if consent.(string) != "allow" {
+		err = errors.ErrAccessDenied
+		return
+	}
+	consented = true
 	return
 }
 
@@ -210,7 +330,7 @@ func loginHandler(w http.ResponseWriter, r *http.Request) {
 			store.Set("LoggedInUserID", r.Form.Get("username"))
 			store.Save()
 
-			w.Header().Set("Location", "/auth")
+			w.Header().Set("Location", "/oauth/authorize")
 			w.WriteHeader(http.StatusFound)
 			return
 		} else {
@@ -237,6 +357,15 @@ func authHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.Method == "POST" {
+		store.Set("Consent", r.FormValue("consent"))
+		store.Save()
+
+		w.Header().Set("Location", "/oauth/authorize")
+		w.WriteHeader(http.StatusFound)
+		return
+	}
+
 	outputHTML(w, r, "static/auth.html")
 }
 
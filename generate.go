@@ -25,4 +25,15 @@ type (
 	AccessGenerate interface {
 		Token(ctx context.Context, data *GenerateBasic, isGenRefresh bool) (access, refresh string, err error)
 	}
+
+	// DeviceCodeGenerate generate the device code and user code interface
+	DeviceCodeGenerate interface {
+		Token(ctx context.Context, data *GenerateBasic) (deviceCode, userCode string, err error)
+	}
+
+	// IDTokenGenerate generate the OpenID Connect id token interface,
+	// code is the authorization code the token is issued for, if any
+	IDTokenGenerate interface {
+		Token(ctx context.Context, data *GenerateBasic, code string) (idToken string, err error)
+	}
 )
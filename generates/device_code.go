@@ -0,0 +1,47 @@
+package generates
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"math/big"
+	"strings"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/google/uuid"
+)
+
+// UserCodeCharset the characters of the generated user code,
+// vowels and ambiguous characters are left out (RFC 8628 section 6.1)
+const UserCodeCharset = "BCDFGHJKLMNPQRSTVWXZ"
+
+// NewDeviceCodeGenerate create to generate the device code instance
+func NewDeviceCodeGenerate() *DeviceCodeGenerate {
+	return &DeviceCodeGenerate{UserCodeLength: 8}
+}
+
+// DeviceCodeGenerate generate the device code and user code
+type DeviceCodeGenerate struct {
+	UserCodeLength int
+}
+
+// Token based on the UUID generated device code and a random user code
+func (dg *DeviceCodeGenerate) Token(ctx context.Context, data *oauth2.GenerateBasic) (string, string, error) {
+	buf := bytes.NewBufferString(data.Client.GetID())
+	token := uuid.NewSHA1(uuid.Must(uuid.NewRandom()), buf.Bytes())
+	code := base64.URLEncoding.EncodeToString([]byte(token.String()))
+	code = strings.ToUpper(strings.TrimRight(code, "="))
+
+	userCode := make([]byte, dg.UserCodeLength)
+	max := big.NewInt(int64(len(UserCodeCharset)))
+	for i := range userCode {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", "", err
+		}
+		userCode[i] = UserCodeCharset[n.Int64()]
+	}
+
+	return code, string(userCode), nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-oauth2/oauth2/v4"
 	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
@@ -15,6 +16,9 @@ import (
 // JWTAccessClaims jwt claims
 type JWTAccessClaims struct {
 	jwt.RegisteredClaims
+	Actor                *oauth2.Actor               `json:"act,omitempty"`
+	Confirmation         *oauth2.Confirmation        `json:"cnf,omitempty"`
+	AuthorizationDetails oauth2.AuthorizationDetails `json:"authorization_details,omitempty"`
 }
 
 // Valid claims verification
@@ -34,11 +38,20 @@ func NewJWTAccessGenerate(kid string, key []byte, method jwt.SigningMethod) *JWT
 	}
 }
 
+// NewJWTAccessGenerateWithKeyRing create to generate the jwt access token instance,
+// the tokens are signed with the active key of the key ring
+func NewJWTAccessGenerateWithKeyRing(ring *KeyRing) *JWTAccessGenerate {
+	return &JWTAccessGenerate{
+		KeyRing: ring,
+	}
+}
+
 // JWTAccessGenerate generate the jwt access token
 type JWTAccessGenerate struct {
 	SignedKeyID  string
 	SignedKey    []byte
 	SignedMethod jwt.SigningMethod
+	KeyRing      *KeyRing
 }
 
 // Token based on the UUID generated token
@@ -50,37 +63,20 @@ func (a *JWTAccessGenerate) Token(ctx context.Context, data *oauth2.GenerateBasi
 			ExpiresAt: jwt.NewNumericDate(data.TokenInfo.GetAccessCreateAt().Add(data.TokenInfo.GetAccessExpiresIn())),
 		},
 	}
-
-	token := jwt.NewWithClaims(a.SignedMethod, claims)
-	if a.SignedKeyID != "" {
-		token.Header["kid"] = a.SignedKeyID
+	if ati, ok := data.TokenInfo.(oauth2.AudienceTokenInfo); ok && len(ati.GetAudience()) > 0 {
+		claims.Audience = jwt.ClaimStrings(ati.GetAudience())
 	}
-	var key interface{}
-	if a.isEs() {
-		v, err := jwt.ParseECPrivateKeyFromPEM(a.SignedKey)
-		if err != nil {
-			return "", "", err
-		}
-		key = v
-	} else if a.isRsOrPS() {
-		v, err := jwt.ParseRSAPrivateKeyFromPEM(a.SignedKey)
-		if err != nil {
-			return "", "", err
-		}
-		key = v
-	} else if a.isHs() {
-		key = a.SignedKey
-	} else if a.isEd() {
-		v, err := jwt.ParseEdPrivateKeyFromPEM(a.SignedKey)
-		if err != nil {
-			return "", "", err
-		}
-		key = v
-	} else {
-		return "", "", errors.New("unsupported sign method")
+	if dti, ok := data.TokenInfo.(oauth2.DelegatedTokenInfo); ok {
+		claims.Actor = dti.GetActor()
+	}
+	if bti, ok := data.TokenInfo.(oauth2.BoundTokenInfo); ok {
+		claims.Confirmation = bti.GetConfirmation()
+	}
+	if dti, ok := data.TokenInfo.(oauth2.AuthorizationDetailsTokenInfo); ok {
+		claims.AuthorizationDetails = dti.GetAuthorizationDetails()
 	}
 
-	access, err := token.SignedString(key)
+	access, err := a.sign(claims)
 	if err != nil {
 		return "", "", err
 	}
@@ -95,20 +91,42 @@ func (a *JWTAccessGenerate) Token(ctx context.Context, data *oauth2.GenerateBasi
 	return access, refresh, nil
 }
 
-func (a *JWTAccessGenerate) isEs() bool {
-	return strings.HasPrefix(a.SignedMethod.Alg(), "ES")
+// get the key to sign the tokens with
+func (a *JWTAccessGenerate) activeKey() (*ringKey, error) {
+	if a.KeyRing != nil {
+		return a.KeyRing.activeKey(time.Now())
+	}
+	return newRingKey(a.SignedKeyID, a.SignedKey, a.SignedMethod)
 }
 
-func (a *JWTAccessGenerate) isRsOrPS() bool {
-	isRs := strings.HasPrefix(a.SignedMethod.Alg(), "RS")
-	isPs := strings.HasPrefix(a.SignedMethod.Alg(), "PS")
-	return isRs || isPs
+// sign the claims with the active signing key
+func (a *JWTAccessGenerate) sign(claims jwt.Claims) (string, error) {
+	key, err := a.activeKey()
+	if err != nil {
+		return "", err
+	}
+	return key.sign(claims)
 }
 
-func (a *JWTAccessGenerate) isHs() bool {
-	return strings.HasPrefix(a.SignedMethod.Alg(), "HS")
-}
+// JWKSet the public keys of the signing keys as json web key set,
+// symmetric keys are never published so the set is empty for them
+func (a *JWTAccessGenerate) JWKSet(ctx context.Context) (*models.JWKSet, error) {
+	if a.KeyRing != nil {
+		return a.KeyRing.JWKSet(ctx)
+	}
+
+	set := &models.JWKSet{Keys: []*models.JWK{}}
+	key, err := a.activeKey()
+	if err != nil {
+		return nil, err
+	} else if key.public == nil {
+		return set, nil
+	}
 
-func (a *JWTAccessGenerate) isEd() bool {
-	return strings.HasPrefix(a.SignedMethod.Alg(), "Ed")
+	jwk, err := models.NewJWK(key.kid, key.method.Alg(), key.public)
+	if err != nil {
+		return nil, err
+	}
+	set.Keys = append(set.Keys, jwk)
+	return set, nil
 }
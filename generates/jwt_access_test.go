@@ -2,6 +2,13 @@ package generates_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"testing"
 	"time"
@@ -25,6 +32,9 @@ func TestJWTAccess(t *testing.T) {
 			TokenInfo: &models.Token{
 				AccessCreateAt:  time.Now(),
 				AccessExpiresIn: time.Second * 120,
+				AuthorizationDetails: oauth2.AuthorizationDetails{
+					{"type": "payment_initiation", "creditorAccount": map[string]interface{}{"iban": "DE02100100109307118603"}},
+				},
 			},
 		}
 
@@ -50,5 +60,60 @@ func TestJWTAccess(t *testing.T) {
 		So(len(aud), ShouldEqual, 1)
 		So(aud[0], ShouldEqual, "123456")
 		So(claims.Subject, ShouldEqual, "000000")
+		So(claims.AuthorizationDetails, ShouldHaveLength, 1)
+		So(claims.AuthorizationDetails[0].GetType(), ShouldEqual, "payment_initiation")
+	})
+}
+
+func TestJWTAccessJWKSet(t *testing.T) {
+	Convey("Test JWT Access Generate key set", t, func() {
+		rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+		ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+		ecDER, _ := x509.MarshalECPrivateKey(ecKey)
+		edDER, _ := x509.MarshalPKCS8PrivateKey(edKey)
+
+		keys := []struct {
+			pem    []byte
+			method jwt.SigningMethod
+		}{
+			{pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}), jwt.SigningMethodRS256},
+			{pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER}), jwt.SigningMethodES256},
+			{pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edDER}), jwt.SigningMethodEdDSA},
+		}
+
+		data := &oauth2.GenerateBasic{
+			Client: &models.Client{ID: "123456"},
+			UserID: "000000",
+			TokenInfo: &models.Token{
+				AccessCreateAt:  time.Now(),
+				AccessExpiresIn: time.Second * 120,
+			},
+		}
+
+		for _, k := range keys {
+			gen := generates.NewJWTAccessGenerate("key-"+k.method.Alg(), k.pem, k.method)
+			access, _, err := gen.Token(context.Background(), data, false)
+			So(err, ShouldBeNil)
+
+			set, err := gen.JWKSet(context.Background())
+			So(err, ShouldBeNil)
+			So(len(set.Keys), ShouldEqual, 1)
+			So(set.Keys[0].Alg, ShouldEqual, k.method.Alg())
+
+			_, err = jwt.ParseWithClaims(access, &generates.JWTAccessClaims{}, func(t *jwt.Token) (interface{}, error) {
+				jwk := set.Key(t.Header["kid"].(string))
+				if jwk == nil {
+					return nil, fmt.Errorf("unknown key")
+				}
+				return jwk.PublicKey()
+			})
+			So(err, ShouldBeNil)
+		}
+
+		gen := generates.NewJWTAccessGenerate("", []byte("00000000"), jwt.SigningMethodHS512)
+		set, err := gen.JWKSet(context.Background())
+		So(err, ShouldBeNil)
+		So(set.Keys, ShouldBeEmpty)
 	})
 }
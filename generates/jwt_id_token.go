@@ -0,0 +1,110 @@
+package generates
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"hash"
+	"strings"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// IDTokenClaimsHandler provide the user claims of the id token (e.g. name, email)
+type IDTokenClaimsHandler func(ctx context.Context, data *oauth2.GenerateBasic) (claims map[string]interface{}, err error)
+
+// NewJWTIDTokenGenerate create to generate the OpenID Connect id token instance,
+// the id token is signed with the key of the jwt access token generate
+func NewJWTIDTokenGenerate(issuer string, signer *JWTAccessGenerate) *JWTIDTokenGenerate {
+	return &JWTIDTokenGenerate{
+		Issuer:    issuer,
+		ExpiresIn: time.Hour,
+		Signer:    signer,
+	}
+}
+
+// JWTIDTokenGenerate generate the OpenID Connect id token
+type JWTIDTokenGenerate struct {
+	Issuer        string
+	ExpiresIn     time.Duration
+	Signer        *JWTAccessGenerate
+	ClaimsHandler IDTokenClaimsHandler
+}
+
+// Token generate the signed id token
+// https://openid.net/specs/openid-connect-core-1_0.html#IDToken
+func (g *JWTIDTokenGenerate) Token(ctx context.Context, data *oauth2.GenerateBasic, code string) (string, error) {
+	claims := jwt.MapClaims{}
+	if fn := g.ClaimsHandler; fn != nil {
+		userClaims, err := fn(ctx, data)
+		if err != nil {
+			return "", err
+		}
+		for k, v := range userClaims {
+			claims[k] = v
+		}
+	}
+
+	claims["iss"] = g.Issuer
+	claims["sub"] = data.UserID
+	claims["aud"] = data.Client.GetID()
+	claims["iat"] = data.CreateAt.Unix()
+	claims["exp"] = data.CreateAt.Add(g.ExpiresIn).Unix()
+
+	if ti, ok := data.TokenInfo.(oauth2.OpenIDTokenInfo); ok {
+		if nonce := ti.GetNonce(); nonce != "" {
+			claims["nonce"] = nonce
+		}
+		if authTime := ti.GetAuthTime(); !authTime.IsZero() {
+			claims["auth_time"] = authTime.Unix()
+		}
+	}
+
+	key, err := g.Signer.activeKey()
+	if err != nil {
+		return "", err
+	}
+
+	if access := data.TokenInfo.GetAccess(); access != "" {
+		v, err := leftHalfHash(key.method, access)
+		if err != nil {
+			return "", err
+		}
+		claims["at_hash"] = v
+	}
+
+	if code != "" {
+		v, err := leftHalfHash(key.method, code)
+		if err != nil {
+			return "", err
+		}
+		claims["c_hash"] = v
+	}
+
+	return key.sign(claims)
+}
+
+// the base64url encoding of the left-most half of the hash of the value,
+// where the hash algorithm is the one used by the signing method
+func leftHalfHash(method jwt.SigningMethod, value string) (string, error) {
+	var h hash.Hash
+	alg := method.Alg()
+	switch {
+	case strings.HasSuffix(alg, "256"):
+		h = sha256.New()
+	case strings.HasSuffix(alg, "384"):
+		h = sha512.New384()
+	case strings.HasSuffix(alg, "512"), alg == "EdDSA":
+		h = sha512.New()
+	default:
+		return "", errors.New("unsupported sign method")
+	}
+
+	h.Write([]byte(value))
+	sum := h.Sum(nil)
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2]), nil
+}
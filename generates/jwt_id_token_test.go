@@ -0,0 +1,53 @@
+package generates_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/generates"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/golang-jwt/jwt/v5"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestJWTIDToken(t *testing.T) {
+	Convey("Test JWT ID Token Generate", t, func() {
+		authTime := time.Now().Add(-time.Minute)
+		data := &oauth2.GenerateBasic{
+			Client:   &models.Client{ID: "123456"},
+			UserID:   "000000",
+			CreateAt: time.Now(),
+			TokenInfo: &models.Token{
+				Access:   "access_token_value",
+				Nonce:    "n-0S6_WzA2Mj",
+				AuthTime: authTime,
+			},
+		}
+
+		gen := generates.NewJWTIDTokenGenerate("https://as.example.com",
+			generates.NewJWTAccessGenerate("kid1", []byte("00000000"), jwt.SigningMethodHS256))
+		gen.ClaimsHandler = func(ctx context.Context, data *oauth2.GenerateBasic) (map[string]interface{}, error) {
+			return map[string]interface{}{"name": "Jane Doe", "iss": "overridden"}, nil
+		}
+		idToken, err := gen.Token(context.Background(), data, "code_value")
+		So(err, ShouldBeNil)
+
+		claims := jwt.MapClaims{}
+		token, err := jwt.ParseWithClaims(idToken, claims, func(t *jwt.Token) (interface{}, error) {
+			return []byte("00000000"), nil
+		})
+		So(err, ShouldBeNil)
+		So(token.Header["kid"], ShouldEqual, "kid1")
+		So(claims["iss"], ShouldEqual, "https://as.example.com")
+		So(claims["sub"], ShouldEqual, "000000")
+		So(claims["aud"], ShouldEqual, "123456")
+		So(claims["nonce"], ShouldEqual, "n-0S6_WzA2Mj")
+		So(claims["auth_time"], ShouldEqual, float64(authTime.Unix()))
+		So(claims["name"], ShouldEqual, "Jane Doe")
+		So(claims["at_hash"], ShouldEqual, "f4MawJHWTsTdJriR7IklQw")
+		So(claims["c_hash"], ShouldNotBeEmpty)
+	})
+}
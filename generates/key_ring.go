@@ -0,0 +1,248 @@
+package generates
+
+import (
+	"context"
+	"crypto"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey the signing key of the key ring
+type SigningKey struct {
+	// key id, the json web key thumbprint of the public key is used if empty
+	ID string
+	// PEM encoded private key, or the secret of the HMAC signing methods
+	Key    []byte
+	Method jwt.SigningMethod
+	// the time the key becomes the active signing key, zero means immediately
+	ActivateAt time.Time
+	// the time the key stops signing tokens, zero means never
+	RetireAt time.Time
+}
+
+// NewKeyRing create to signing key ring instance,
+// maxTokenLifetime is the longest lifetime of the tokens signed by the keys
+func NewKeyRing(maxTokenLifetime time.Duration, keys ...*SigningKey) (*KeyRing, error) {
+	ring := &KeyRing{maxTokenLifetime: maxTokenLifetime}
+	for _, key := range keys {
+		if err := ring.Add(key); err != nil {
+			return nil, err
+		}
+	}
+	return ring, nil
+}
+
+// KeyRing the signing keys of the tokens,
+// the key activated last of the keys neither pending nor retired is the active signing key,
+// all other keys are only used to verify the tokens.
+// The public keys are published until every token signed with them has expired.
+type KeyRing struct {
+	mu               sync.RWMutex
+	maxTokenLifetime time.Duration
+	keys             []*ringKey
+}
+
+// Add add a signing key to the key ring
+func (kr *KeyRing) Add(key *SigningKey) error {
+	rk, err := newRingKey(key.ID, key.Key, key.Method)
+	if err != nil {
+		return err
+	}
+
+	if rk.kid == "" {
+		if rk.public == nil {
+			return errors.New("the key id of a symmetric key is required")
+		}
+		jwk, err := models.NewJWK("", "", rk.public)
+		if err != nil {
+			return err
+		}
+		if rk.kid, err = jwk.Thumbprint(); err != nil {
+			return err
+		}
+	}
+	rk.ring = kr
+	rk.activateAt = key.ActivateAt
+	rk.retireAt = key.RetireAt
+
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	for _, k := range kr.keys {
+		if k.kid == rk.kid {
+			return errors.New("duplicate key id")
+		}
+	}
+	kr.keys = append(kr.keys, rk)
+	return nil
+}
+
+// Retire stop signing tokens with the key from the time on
+func (kr *KeyRing) Retire(kid string, at time.Time) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	for _, k := range kr.keys {
+		if k.kid == kid {
+			k.retireAt = at
+			return nil
+		}
+	}
+	return errors.New("key not found")
+}
+
+// JWKSet the public keys that may have signed tokens which are not yet expired,
+// as well as the keys pending activation
+func (kr *KeyRing) JWKSet(ctx context.Context) (*models.JWKSet, error) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	now := time.Now()
+	set := &models.JWKSet{Keys: []*models.JWK{}}
+	for _, k := range kr.keys {
+		if k.public == nil || !kr.isPublished(k, now) {
+			continue
+		}
+		jwk, err := models.NewJWK(k.kid, k.method.Alg(), k.public)
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
+
+// Keyfunc provide the verification key of the token for jwt.Parse
+func (kr *KeyRing) Keyfunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	now := time.Now()
+	for _, k := range kr.keys {
+		if k.kid != kid || !kr.isPublished(k, now) {
+			continue
+		} else if k.method.Alg() != t.Method.Alg() {
+			return nil, errors.New("unexpected sign method")
+		}
+
+		if k.public != nil {
+			return k.public, nil
+		}
+		return k.private, nil
+	}
+	return nil, errors.New("key not found")
+}
+
+// get the active signing key
+func (kr *KeyRing) activeKey(now time.Time) (*ringKey, error) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	var active *ringKey
+	for _, k := range kr.keys {
+		if k.activateAt.After(now) || (!k.retireAt.IsZero() && !k.retireAt.After(now)) {
+			continue
+		}
+		if active == nil || k.activateAt.After(active.activateAt) {
+			active = k
+		}
+	}
+
+	if active == nil {
+		return nil, errors.New("no active signing key")
+	}
+	return active, nil
+}
+
+// whether the key may be needed to verify tokens
+func (kr *KeyRing) isPublished(k *ringKey, now time.Time) bool {
+	if k.retireAt.IsZero() {
+		return true
+	}
+	return now.Before(k.retireAt.Add(kr.maxTokenLifetime)) || now.Before(k.signedUntil)
+}
+
+// record the expiration time of a token signed with the key
+func (kr *KeyRing) signed(k *ringKey, claims jwt.Claims) {
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return
+	}
+
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	if exp.After(k.signedUntil) {
+		k.signedUntil = exp.Time
+	}
+}
+
+// the parsed signing key
+type ringKey struct {
+	kid         string
+	method      jwt.SigningMethod
+	private     interface{}
+	public      crypto.PublicKey
+	activateAt  time.Time
+	retireAt    time.Time
+	signedUntil time.Time
+	ring        *KeyRing
+}
+
+func newRingKey(kid string, key []byte, method jwt.SigningMethod) (*ringKey, error) {
+	if method == nil {
+		return nil, errors.New("unsupported sign method")
+	}
+
+	rk := &ringKey{
+		kid:    kid,
+		method: method,
+	}
+
+	var err error
+	alg := method.Alg()
+	if strings.HasPrefix(alg, "ES") {
+		rk.private, err = jwt.ParseECPrivateKeyFromPEM(key)
+	} else if strings.HasPrefix(alg, "RS") || strings.HasPrefix(alg, "PS") {
+		rk.private, err = jwt.ParseRSAPrivateKeyFromPEM(key)
+	} else if strings.HasPrefix(alg, "HS") {
+		rk.private = key
+	} else if strings.HasPrefix(alg, "Ed") {
+		rk.private, err = jwt.ParseEdPrivateKeyFromPEM(key)
+	} else {
+		return nil, errors.New("unsupported sign method")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if signer, ok := rk.private.(crypto.Signer); ok {
+		rk.public = signer.Public()
+	}
+	return rk, nil
+}
+
+// sign the claims with the key
+func (k *ringKey) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.method, claims)
+	if k.kid != "" {
+		token.Header["kid"] = k.kid
+	}
+
+	v, err := token.SignedString(k.private)
+	if err != nil {
+		return "", err
+	}
+
+	if k.ring != nil {
+		k.ring.signed(k, claims)
+	}
+	return v, nil
+}
@@ -0,0 +1,114 @@
+package generates_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/generates"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/golang-jwt/jwt/v5"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func newECKey() []byte {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalECPrivateKey(key)
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
+func TestKeyRing(t *testing.T) {
+	Convey("Test signing key rotation", t, func() {
+		ctx := context.Background()
+		now := time.Now()
+		data := &oauth2.GenerateBasic{
+			Client: &models.Client{ID: "123456"},
+			UserID: "000000",
+			TokenInfo: &models.Token{
+				AccessCreateAt:  now,
+				AccessExpiresIn: time.Hour,
+			},
+		}
+		kidOf := func(access string) string {
+			token, _, err := jwt.NewParser().ParseUnverified(access, jwt.MapClaims{})
+			So(err, ShouldBeNil)
+			return token.Header["kid"].(string)
+		}
+
+		ring, err := generates.NewKeyRing(time.Minute,
+			&generates.SigningKey{ID: "key1", Key: newECKey(), Method: jwt.SigningMethodES256},
+			&generates.SigningKey{Key: newECKey(), Method: jwt.SigningMethodES256, ActivateAt: now.Add(time.Hour)},
+		)
+		So(err, ShouldBeNil)
+		gen := generates.NewJWTAccessGenerateWithKeyRing(ring)
+
+		set, err := gen.JWKSet(ctx)
+		So(err, ShouldBeNil)
+		So(len(set.Keys), ShouldEqual, 2)
+		thumbprint, err := set.Keys[1].Thumbprint()
+		So(err, ShouldBeNil)
+		So(set.Keys[1].Kid, ShouldEqual, thumbprint)
+
+		// the pending key is published but not used
+		access, _, err := gen.Token(ctx, data, false)
+		So(err, ShouldBeNil)
+		So(kidOf(access), ShouldEqual, "key1")
+
+		// rotate to a new key, the retired key still verifies its tokens
+		So(ring.Retire("key1", now), ShouldBeNil)
+		So(ring.Add(&generates.SigningKey{ID: "key3", Key: newECKey(), Method: jwt.SigningMethodES256}), ShouldBeNil)
+
+		next, _, err := gen.Token(ctx, data, false)
+		So(err, ShouldBeNil)
+		So(kidOf(next), ShouldEqual, "key3")
+
+		for _, v := range []string{access, next} {
+			_, err = jwt.ParseWithClaims(v, &generates.JWTAccessClaims{}, ring.Keyfunc)
+			So(err, ShouldBeNil)
+		}
+
+		set, err = gen.JWKSet(ctx)
+		So(err, ShouldBeNil)
+		So(len(set.Keys), ShouldEqual, 3)
+		So(set.Key("key1"), ShouldNotBeNil)
+	})
+
+	Convey("Test retired key publishing", t, func() {
+		ctx := context.Background()
+		now := time.Now()
+		data := &oauth2.GenerateBasic{
+			Client: &models.Client{ID: "123456"},
+			TokenInfo: &models.Token{
+				AccessCreateAt:  now,
+				AccessExpiresIn: time.Hour,
+			},
+		}
+
+		ring, err := generates.NewKeyRing(0,
+			&generates.SigningKey{ID: "key1", Key: newECKey(), Method: jwt.SigningMethodES256},
+			&generates.SigningKey{ID: "key2", Key: newECKey(), Method: jwt.SigningMethodES256, RetireAt: now.Add(-time.Second)},
+			&generates.SigningKey{ID: "key3", Key: []byte("00000000"), Method: jwt.SigningMethodHS256, ActivateAt: now.Add(time.Hour)},
+		)
+		So(err, ShouldBeNil)
+
+		_, _, err = generates.NewJWTAccessGenerateWithKeyRing(ring).Token(ctx, data, false)
+		So(err, ShouldBeNil)
+		So(ring.Retire("key1", now), ShouldBeNil)
+
+		// key1 signed a token which is still valid, key2 did not sign any token
+		set, err := ring.JWKSet(ctx)
+		So(err, ShouldBeNil)
+		So(len(set.Keys), ShouldEqual, 1)
+		So(set.Keys[0].Kid, ShouldEqual, "key1")
+
+		_, err = generates.NewKeyRing(0, &generates.SigningKey{Key: []byte("00000000"), Method: jwt.SigningMethodHS256})
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -3,23 +3,39 @@ package oauth2
 import (
 	"context"
 	"net/http"
+	"net/url"
 	"time"
 )
 
 // TokenGenerateRequest provide to generate the token request parameters
 type TokenGenerateRequest struct {
-	ClientID            string
-	ClientSecret        string
-	UserID              string
-	RedirectURI         string
-	Scope               string
-	Code                string
-	CodeChallenge       string
-	CodeChallengeMethod CodeChallengeMethod
-	Refresh             string
-	CodeVerifier        string
-	AccessTokenExp      time.Duration
-	Request             *http.Request
+	ClientID             string
+	ClientSecret         string
+	ClientAuthMethod     ClientAuthMethod // the method the client is already authenticated with, the secret isn't compared then
+	UserID               string
+	RedirectURI          string
+	Scope                string
+	Code                 string
+	DeviceCode           string
+	Assertion            string
+	SubjectToken         string
+	SubjectTokenType     string
+	ActorToken           string
+	ActorTokenType       string
+	RequestedTokenType   string
+	Resource             []string
+	Audience             []string
+	AuthorizationDetails AuthorizationDetails
+	Actor                *Actor
+	Confirmation         *Confirmation
+	CodeChallenge        string
+	CodeChallengeMethod  CodeChallengeMethod
+	Refresh              string
+	CodeVerifier         string
+	Nonce                string
+	AuthTime             time.Time
+	AccessTokenExp       time.Duration
+	Request              *http.Request
 }
 
 // Manager authorization management interface
@@ -48,3 +64,56 @@ type Manager interface {
 	// according to the refresh token for corresponding token information
 	LoadRefreshToken(ctx context.Context, refresh string) (ti TokenInfo, err error)
 }
+
+// ClientRegistrationManager dynamic client registration management interface,
+// the clients are stored in the writable client store
+type ClientRegistrationManager interface {
+	// create and store the new client
+	CreateClient(ctx context.Context, cli ClientInfo) (err error)
+
+	// replace the stored client
+	UpdateClient(ctx context.Context, cli ClientInfo) (err error)
+
+	// delete the client
+	RemoveClient(ctx context.Context, clientID string) (err error)
+}
+
+// PushedRequestManager pushed authorization request management interface
+type PushedRequestManager interface {
+	// store the parameters of the authorization request and generate the request uri
+	PushAuthorizeRequest(ctx context.Context, clientID string, params url.Values) (pi PushedRequestInfo, err error)
+
+	// according to the request uri for the pushed authorization request of the client
+	LoadPushedRequest(ctx context.Context, clientID, requestURI string) (pi PushedRequestInfo, err error)
+
+	// use the request uri to delete the pushed authorization request
+	RemovePushedRequest(ctx context.Context, requestURI string) (err error)
+}
+
+// ConsentManager user consent management interface
+type ConsentManager interface {
+	// record the scope the user granted to the client, the scope is added to the scope granted before
+	GrantConsent(ctx context.Context, userID, clientID, scope string) (ci ConsentInfo, err error)
+
+	// get the unexpired consent of the user to the client, nil if the user never consented
+	LoadConsent(ctx context.Context, userID, clientID string) (ci ConsentInfo, err error)
+
+	// get the unexpired consents of the user
+	ListConsents(ctx context.Context, userID string) (cis []ConsentInfo, err error)
+
+	// withdraw the consent of the user to the client
+	RevokeConsent(ctx context.Context, userID, clientID string) (err error)
+}
+
+// DeviceAuthorizationManager device authorization grant management interface,
+// the access token is generated by GenerateAccessToken with the DeviceCode grant type
+type DeviceAuthorizationManager interface {
+	// generate the device code and user code
+	GenerateDeviceCode(ctx context.Context, tgr *TokenGenerateRequest) (di DeviceCodeInfo, err error)
+
+	// according to the user code for the pending device authorization
+	LoadDeviceCode(ctx context.Context, userCode string) (di DeviceCodeInfo, err error)
+
+	// approve or deny the pending device authorization of the user code
+	AuthorizeDeviceCode(ctx context.Context, userCode, userID string, approved bool) (err error)
+}
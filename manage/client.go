@@ -0,0 +1,114 @@
+package manage
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// get the writable client store
+func (m *Manager) registrationStore() (oauth2.ClientRegistrationStore, error) {
+	if stor, ok := m.clientStore.(oauth2.ClientRegistrationStore); ok {
+		return stor, nil
+	}
+	return nil, errors.ErrReadOnlyClientStore
+}
+
+// CreateClient create and store the new client
+func (m *Manager) CreateClient(ctx context.Context, cli oauth2.ClientInfo) error {
+	stor, err := m.registrationStore()
+	if err != nil {
+		return err
+	}
+	return stor.Create(ctx, cli)
+}
+
+// UpdateClient replace the stored client
+func (m *Manager) UpdateClient(ctx context.Context, cli oauth2.ClientInfo) error {
+	stor, err := m.registrationStore()
+	if err != nil {
+		return err
+	}
+	return stor.Update(ctx, cli)
+}
+
+// RemoveClient delete the client
+func (m *Manager) RemoveClient(ctx context.Context, clientID string) error {
+	stor, err := m.registrationStore()
+	if err != nil {
+		return err
+	}
+	return stor.RemoveByID(ctx, clientID)
+}
+
+// check the client allows the grant type
+func allowedGrantType(cli oauth2.ClientInfo, gt oauth2.GrantType) bool {
+	gcli, ok := cli.(oauth2.GrantTypesClientInfo)
+	if !ok || len(gcli.GetGrantTypes()) == 0 {
+		return true
+	}
+	for _, v := range gcli.GetGrantTypes() {
+		if v == gt {
+			return true
+		}
+	}
+	return false
+}
+
+// check the client allows the response type
+func allowedResponseType(cli oauth2.ClientInfo, rt oauth2.ResponseType) bool {
+	rcli, ok := cli.(oauth2.ResponseTypesClientInfo)
+	if !ok || len(rcli.GetResponseTypes()) == 0 {
+		return true
+	}
+	for _, v := range rcli.GetResponseTypes() {
+		if v == rt {
+			return true
+		}
+	}
+	return false
+}
+
+// check the client allows every value of the space-delimited scope
+func allowedScope(cli oauth2.ClientInfo, scope string, match oauth2.ScopeMatcher) bool {
+	scli, ok := cli.(oauth2.ScopeClientInfo)
+	if !ok || scli.GetScope() == "" {
+		return true
+	}
+	return impliesScope(scli.GetScope(), scope, match)
+}
+
+// check the client allows all requested resources
+func allowedResource(cli oauth2.ClientInfo, resources []string) bool {
+	rcli, ok := cli.(oauth2.ResourceClientInfo)
+	if !ok || len(rcli.GetResources()) == 0 {
+		return true
+	}
+	return oauth2.WithinAudience(rcli.GetResources(), resources)
+}
+
+// get the token lifetimes of the client, the zero lifetime keeps the lifetime of the grant type
+func clientTokenExp(cli oauth2.ClientInfo) (access, refresh time.Duration) {
+	if tcli, ok := cli.(oauth2.TokenExpClientInfo); ok {
+		return tcli.GetAccessTokenExp(), tcli.GetRefreshTokenExp()
+	}
+	return 0, 0
+}
+
+// validate the redirect uri matches one of the registered redirect uris of the client,
+// or the domain of the client when none is registered
+func (m *Manager) validateClientURI(cli oauth2.ClientInfo, redirectURI string) error {
+	uris := []string{cli.GetDomain()}
+	if rcli, ok := cli.(oauth2.RedirectURIsClientInfo); ok && len(rcli.GetRedirectURIs()) > 0 {
+		uris = rcli.GetRedirectURIs()
+	}
+
+	for _, v := range uris {
+		if m.validateURI(v, redirectURI) == nil {
+			return nil
+		}
+	}
+	return errors.ErrInvalidRedirectURI
+}
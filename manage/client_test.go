@@ -0,0 +1,200 @@
+package manage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/go-oauth2/oauth2/v4/store"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClientMetadata(t *testing.T) {
+	Convey("Client metadata test", t, func() {
+		manager := manage.NewDefaultManager()
+		ctx := context.Background()
+
+		manager.MustTokenStorage(store.NewMemoryTokenStore())
+
+		clientStore := store.NewClientStore()
+		_ = clientStore.Set("1", &models.Client{
+			ID:     "1",
+			Secret: "11",
+			RedirectURIs: []string{
+				"http://localhost/oauth2",
+				"http://example.com/cb",
+			},
+			GrantTypes:      []oauth2.GrantType{oauth2.AuthorizationCode, oauth2.ClientCredentials},
+			ResponseTypes:   []oauth2.ResponseType{oauth2.Code},
+			Scope:           "read write repo project:*:read",
+			Resources:       []string{"https://billing.example.com", "https://admin.example.com"},
+			AccessTokenExp:  time.Minute * 5,
+			RefreshTokenExp: time.Hour,
+		})
+		_ = clientStore.Set("2", &models.Client{
+			ID:       "2",
+			Secret:   "22",
+			Disabled: true,
+		})
+		manager.MapClientStorage(clientStore)
+
+		Convey("Disabled client test", func() {
+			_, err := manager.GetClient(ctx, "2")
+			So(err, ShouldEqual, errors.ErrInvalidClient)
+		})
+
+		Convey("Redirect uris test", func() {
+			for _, uri := range []string{"http://localhost/oauth2", "http://example.com/cb"} {
+				ti, err := manager.GenerateAuthToken(ctx, oauth2.Code, &oauth2.TokenGenerateRequest{
+					ClientID:    "1",
+					UserID:      "123456",
+					RedirectURI: uri,
+					Scope:       "read",
+				})
+				So(err, ShouldBeNil)
+				So(ti.GetAccessExpiresIn(), ShouldEqual, time.Minute*5)
+			}
+
+			_, err := manager.GenerateAuthToken(ctx, oauth2.Code, &oauth2.TokenGenerateRequest{
+				ClientID:    "1",
+				RedirectURI: "http://other.com/cb",
+			})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Response type test", func() {
+			_, err := manager.GenerateAuthToken(ctx, oauth2.Token, &oauth2.TokenGenerateRequest{
+				ClientID:    "1",
+				UserID:      "123456",
+				RedirectURI: "http://localhost/oauth2",
+			})
+			So(err, ShouldEqual, errors.ErrUnauthorizedClient)
+		})
+
+		Convey("Grant type and scope test", func() {
+			ti, err := manager.GenerateAccessToken(ctx, oauth2.ClientCredentials, &oauth2.TokenGenerateRequest{
+				ClientID:     "1",
+				ClientSecret: "11",
+				Scope:        "read write",
+			})
+			So(err, ShouldBeNil)
+			So(ti.GetAccessExpiresIn(), ShouldEqual, time.Minute*5)
+			// the refreshing grant isn't allowed
+			So(ti.GetRefresh(), ShouldBeEmpty)
+
+			_, err = manager.GenerateAccessToken(ctx, oauth2.ClientCredentials, &oauth2.TokenGenerateRequest{
+				ClientID:     "1",
+				ClientSecret: "11",
+				Scope:        "read admin",
+			})
+			So(err, ShouldEqual, errors.ErrInvalidScope)
+
+			// the scope tokens only match the same scope token by default
+			_, err = manager.GenerateAccessToken(ctx, oauth2.ClientCredentials, &oauth2.TokenGenerateRequest{
+				ClientID:     "1",
+				ClientSecret: "11",
+				Scope:        "repo:write",
+			})
+			So(err, ShouldEqual, errors.ErrInvalidScope)
+
+			// the descendants and wildcard matches of the hierarchical scopes
+			manager.SetScopeMatcher(oauth2.HierarchicalScopes("repo", "project:*:read"))
+			_, err = manager.GenerateAccessToken(ctx, oauth2.ClientCredentials, &oauth2.TokenGenerateRequest{
+				ClientID:     "1",
+				ClientSecret: "11",
+				Scope:        "repo:write project:1234:read",
+			})
+			So(err, ShouldBeNil)
+
+			_, err = manager.GenerateAccessToken(ctx, oauth2.ClientCredentials, &oauth2.TokenGenerateRequest{
+				ClientID:     "1",
+				ClientSecret: "11",
+				Scope:        "project:1234:write",
+			})
+			So(err, ShouldEqual, errors.ErrInvalidScope)
+
+			_, err = manager.GenerateAccessToken(ctx, oauth2.PasswordCredentials, &oauth2.TokenGenerateRequest{
+				ClientID:     "1",
+				ClientSecret: "11",
+				UserID:       "123456",
+			})
+			So(err, ShouldEqual, errors.ErrUnauthorizedClient)
+		})
+
+		Convey("Resource indicators test", func() {
+			code, err := manager.GenerateAuthToken(ctx, oauth2.Code, &oauth2.TokenGenerateRequest{
+				ClientID:    "1",
+				UserID:      "123456",
+				RedirectURI: "http://localhost/oauth2",
+				Scope:       "read",
+				Resource:    []string{"https://billing.example.com", "https://admin.example.com"},
+			})
+			So(err, ShouldBeNil)
+
+			// the access token is narrowed to one of the resources of the authorization code
+			ti, err := manager.GenerateAccessToken(ctx, oauth2.AuthorizationCode, &oauth2.TokenGenerateRequest{
+				ClientID:     "1",
+				ClientSecret: "11",
+				RedirectURI:  "http://localhost/oauth2",
+				Code:         code.GetCode(),
+				Resource:     []string{"https://billing.example.com"},
+			})
+			So(err, ShouldBeNil)
+			So(ti.(oauth2.AudienceTokenInfo).GetAudience(), ShouldResemble, []string{"https://billing.example.com"})
+
+			_, err = manager.GenerateAccessToken(ctx, oauth2.ClientCredentials, &oauth2.TokenGenerateRequest{
+				ClientID:     "1",
+				ClientSecret: "11",
+				Resource:     []string{"https://other.example.com"},
+			})
+			So(err, ShouldEqual, errors.ErrInvalidTarget)
+
+			_, err = manager.GenerateAccessToken(ctx, oauth2.ClientCredentials, &oauth2.TokenGenerateRequest{
+				ClientID:     "1",
+				ClientSecret: "11",
+				Audience:     []string{"https://other.example.com"},
+			})
+			So(err, ShouldEqual, errors.ErrInvalidTarget)
+
+			code, err = manager.GenerateAuthToken(ctx, oauth2.Code, &oauth2.TokenGenerateRequest{
+				ClientID:    "1",
+				UserID:      "123456",
+				RedirectURI: "http://localhost/oauth2",
+				Resource:    []string{"https://billing.example.com"},
+			})
+			So(err, ShouldBeNil)
+
+			_, err = manager.GenerateAccessToken(ctx, oauth2.AuthorizationCode, &oauth2.TokenGenerateRequest{
+				ClientID:     "1",
+				ClientSecret: "11",
+				RedirectURI:  "http://localhost/oauth2",
+				Code:         code.GetCode(),
+				Resource:     []string{"https://admin.example.com"},
+			})
+			So(err, ShouldEqual, errors.ErrInvalidTarget)
+
+			// the grant without resources can't be redeemed or refreshed for a resource
+			code, err = manager.GenerateAuthToken(ctx, oauth2.Code, &oauth2.TokenGenerateRequest{
+				ClientID:    "1",
+				UserID:      "123456",
+				RedirectURI: "http://localhost/oauth2",
+			})
+			So(err, ShouldBeNil)
+
+			atParams := &oauth2.TokenGenerateRequest{
+				ClientID:     "1",
+				ClientSecret: "11",
+				RedirectURI:  "http://localhost/oauth2",
+				Code:         code.GetCode(),
+				Resource:     []string{"https://billing.example.com"},
+			}
+			_, err = manager.GenerateAccessToken(ctx, oauth2.AuthorizationCode, atParams)
+			So(err, ShouldEqual, errors.ErrInvalidTarget)
+		})
+	})
+}
@@ -26,6 +26,9 @@ type RefreshingConfig struct {
 	IsRemoveAccess bool
 	// whether to remove refreshing token
 	IsRemoveRefreshing bool
+	// the period the rotated refreshing token can be presented again by the same client,
+	// the token family is revoked when it is reused later, 0 means no grace period
+	ReuseGracePeriod time.Duration
 }
 
 // default configs
@@ -35,5 +38,12 @@ var (
 	DefaultImplicitTokenCfg      = &Config{AccessTokenExp: time.Hour * 1}
 	DefaultPasswordTokenCfg      = &Config{AccessTokenExp: time.Hour * 2, RefreshTokenExp: time.Hour * 24 * 7, IsGenerateRefresh: true}
 	DefaultClientTokenCfg        = &Config{AccessTokenExp: time.Hour * 2}
+	DefaultDeviceCodeExp         = time.Minute * 10
+	DefaultPushedRequestExp      = time.Second * 60
+	DefaultDeviceCodeInterval    = time.Second * 5
+	DefaultDeviceCodeTokenCfg    = &Config{AccessTokenExp: time.Hour * 2, RefreshTokenExp: time.Hour * 24 * 3, IsGenerateRefresh: true}
+	DefaultTokenExchangeTokenCfg = &Config{AccessTokenExp: time.Hour * 1}
+	DefaultJWTBearerTokenCfg     = &Config{AccessTokenExp: time.Hour * 2}
+	DefaultGrantTokenCfg         = &Config{AccessTokenExp: time.Hour * 2}
 	DefaultRefreshTokenCfg       = &RefreshingConfig{IsGenerateRefresh: true, IsRemoveAccess: true, IsRemoveRefreshing: true}
 )
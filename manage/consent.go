@@ -0,0 +1,95 @@
+package manage
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
+)
+
+// check whether the consent is expired
+func consentExpired(ci oauth2.ConsentInfo) bool {
+	exp := ci.GetExpiresIn()
+	return exp > 0 && ci.GetCreateAt().Add(exp).Before(time.Now())
+}
+
+// GrantConsent record the scope the user granted to the client, the scope is added to the scope granted before,
+// the consent isn't recorded when the consent store isn't mapped
+func (m *Manager) GrantConsent(ctx context.Context, userID, clientID, scope string) (oauth2.ConsentInfo, error) {
+	if m.consentStore == nil {
+		return nil, nil
+	}
+
+	ci, err := m.LoadConsent(ctx, userID, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	granted, err := oauth2.ParseScope(scope)
+	if err != nil {
+		return nil, err
+	}
+	if ci != nil {
+		prev, err := oauth2.ParseScope(ci.GetScope())
+		if err != nil {
+			return nil, errors.ErrInvalidConsent
+		}
+		granted = prev.Union(granted)
+	}
+
+	nci := models.NewConsent()
+	nci.SetUserID(userID)
+	nci.SetClientID(clientID)
+	nci.SetScope(granted.String())
+	nci.SetCreateAt(time.Now())
+	nci.SetExpiresIn(m.consentExp)
+	if err := m.consentStore.Save(ctx, nci); err != nil {
+		return nil, err
+	}
+	return nci, nil
+}
+
+// LoadConsent get the unexpired consent of the user to the client, nil if the user never consented
+func (m *Manager) LoadConsent(ctx context.Context, userID, clientID string) (oauth2.ConsentInfo, error) {
+	if m.consentStore == nil {
+		return nil, nil
+	}
+
+	ci, err := m.consentStore.Get(ctx, userID, clientID)
+	if err != nil {
+		return nil, err
+	} else if ci == nil || consentExpired(ci) {
+		return nil, nil
+	}
+	return ci, nil
+}
+
+// ListConsents get the unexpired consents of the user
+func (m *Manager) ListConsents(ctx context.Context, userID string) ([]oauth2.ConsentInfo, error) {
+	if m.consentStore == nil {
+		return nil, nil
+	}
+
+	cis, err := m.consentStore.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var valid []oauth2.ConsentInfo
+	for _, ci := range cis {
+		if !consentExpired(ci) {
+			valid = append(valid, ci)
+		}
+	}
+	return valid, nil
+}
+
+// RevokeConsent withdraw the consent of the user to the client
+func (m *Manager) RevokeConsent(ctx context.Context, userID, clientID string) error {
+	if m.consentStore == nil {
+		return nil
+	}
+	return m.consentStore.Remove(ctx, userID, clientID)
+}
@@ -0,0 +1,162 @@
+package manage
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
+)
+
+// the polling interval is increased by 5 seconds on every slow_down error (RFC 8628 section 3.5)
+const slowDownInterval = time.Second * 5
+
+// the user code is generated again when it collides with the user code of a pending device authorization
+const userCodeAttempts = 5
+
+// NormalizeUserCode normalize the user code entered by the end user,
+// the code is case insensitive and the punctuation is ignored (RFC 8628 section 6.1)
+func NormalizeUserCode(userCode string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return -1
+	}, userCode)
+}
+
+// GenerateDeviceCode generate the device code and user code
+func (m *Manager) GenerateDeviceCode(ctx context.Context, tgr *oauth2.TokenGenerateRequest) (oauth2.DeviceCodeInfo, error) {
+	if m.deviceCodeStore == nil || m.deviceCodeGenerate == nil {
+		return nil, errors.ErrUnsupportedGrantType
+	}
+
+	cli, err := m.GetClient(ctx, tgr.ClientID)
+	if err != nil {
+		return nil, err
+	} else if !allowedGrantType(cli, oauth2.DeviceCode) {
+		return nil, errors.ErrUnauthorizedClient
+	} else if !allowedScope(cli, tgr.Scope, m.matchScope) {
+		return nil, errors.ErrInvalidScope
+	}
+
+	createAt := time.Now()
+	td := &oauth2.GenerateBasic{
+		Client:   cli,
+		CreateAt: createAt,
+		Request:  tgr.Request,
+	}
+
+	exp := m.deviceCodeExp
+	if exp == 0 {
+		exp = DefaultDeviceCodeExp
+	}
+	interval := m.deviceCodeInterval
+	if interval == 0 {
+		interval = DefaultDeviceCodeInterval
+	}
+
+	for i := 1; ; i++ {
+		dv, uv, err := m.deviceCodeGenerate.Token(ctx, td)
+		if err != nil {
+			return nil, err
+		}
+
+		di := models.NewDeviceCode()
+		di.SetClientID(tgr.ClientID)
+		di.SetScope(tgr.Scope)
+		di.SetDeviceCode(dv)
+		di.SetUserCode(NormalizeUserCode(uv))
+		di.SetCreateAt(createAt)
+		di.SetExpiresIn(exp)
+		di.SetInterval(interval)
+		di.SetStatus(oauth2.DeviceCodePending)
+
+		err = m.deviceCodeStore.Create(ctx, di)
+		if err == errors.ErrDuplicateUserCode && i < userCodeAttempts {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		return di, nil
+	}
+}
+
+// LoadDeviceCode according to the user code for the pending device authorization
+func (m *Manager) LoadDeviceCode(ctx context.Context, userCode string) (oauth2.DeviceCodeInfo, error) {
+	if m.deviceCodeStore == nil {
+		return nil, errors.ErrInvalidUserCode
+	}
+
+	di, err := m.deviceCodeStore.GetByUserCode(ctx, NormalizeUserCode(userCode))
+	if err != nil {
+		return nil, err
+	} else if di == nil || di.GetStatus() != oauth2.DeviceCodePending ||
+		di.GetCreateAt().Add(di.GetExpiresIn()).Before(time.Now()) {
+		return nil, errors.ErrInvalidUserCode
+	}
+	return di, nil
+}
+
+// AuthorizeDeviceCode approve or deny the pending device authorization of the user code
+func (m *Manager) AuthorizeDeviceCode(ctx context.Context, userCode, userID string, approved bool) error {
+	di, err := m.LoadDeviceCode(ctx, userCode)
+	if err != nil {
+		return err
+	}
+
+	if approved {
+		di.SetUserID(userID)
+		di.SetStatus(oauth2.DeviceCodeApproved)
+	} else {
+		di.SetStatus(oauth2.DeviceCodeDenied)
+	}
+	return m.deviceCodeStore.Update(ctx, di)
+}
+
+// poll the device authorization of the device code,
+// the device code is removed when the authorization is concluded
+func (m *Manager) pollDeviceCode(ctx context.Context, tgr *oauth2.TokenGenerateRequest) (oauth2.DeviceCodeInfo, error) {
+	if m.deviceCodeStore == nil {
+		return nil, errors.ErrUnsupportedGrantType
+	}
+
+	di, err := m.deviceCodeStore.GetByDeviceCode(ctx, tgr.DeviceCode)
+	if err != nil {
+		return nil, err
+	} else if di == nil || di.GetClientID() != tgr.ClientID {
+		return nil, errors.ErrInvalidDeviceCode
+	}
+
+	now := time.Now()
+	if di.GetCreateAt().Add(di.GetExpiresIn()).Before(now) {
+		_ = m.deviceCodeStore.RemoveByDeviceCode(ctx, tgr.DeviceCode)
+		return nil, errors.ErrExpiredToken
+	}
+
+	switch di.GetStatus() {
+	case oauth2.DeviceCodeApproved:
+		return di, m.deviceCodeStore.RemoveByDeviceCode(ctx, tgr.DeviceCode)
+	case oauth2.DeviceCodeDenied:
+		if err := m.deviceCodeStore.RemoveByDeviceCode(ctx, tgr.DeviceCode); err != nil {
+			return nil, err
+		}
+		return nil, errors.ErrAccessDenied
+	}
+
+	pollErr := errors.ErrAuthorizationPending
+	if last := di.GetLastPollAt(); !last.IsZero() && now.Sub(last) < di.GetInterval() {
+		di.SetInterval(di.GetInterval() + slowDownInterval)
+		pollErr = errors.ErrSlowDown
+	}
+	di.SetLastPollAt(now)
+	if err := m.deviceCodeStore.Update(ctx, di); err != nil {
+		return nil, err
+	}
+	return nil, pollErr
+}
@@ -0,0 +1,38 @@
+package manage
+
+import (
+	"context"
+	"time"
+)
+
+// SecurityEventType the type of the security event
+type SecurityEventType string
+
+// define the types of the security event
+const (
+	// the rotated refresh token is presented again outside the grace period
+	RefreshTokenReuse SecurityEventType = "refresh_token_reuse"
+	// the redeemed authorization code is presented again
+	AuthorizationCodeReuse SecurityEventType = "authorization_code_reuse"
+)
+
+// SecurityEvent the security event detected by the manager
+type SecurityEvent struct {
+	Type     SecurityEventType
+	ClientID string
+	UserID   string
+	Family   string
+	CreateAt time.Time
+}
+
+// SecurityEventHandler report the security event
+type SecurityEventHandler func(ctx context.Context, ev *SecurityEvent)
+
+// report the security event to the handler
+func (m *Manager) reportSecurityEvent(ctx context.Context, ev *SecurityEvent) {
+	if m.securityEvent == nil {
+		return
+	}
+	ev.CreateAt = time.Now()
+	m.securityEvent(ctx, ev)
+}
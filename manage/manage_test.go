@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
 	"github.com/go-oauth2/oauth2/v4/manage"
 	"github.com/go-oauth2/oauth2/v4/models"
 	"github.com/go-oauth2/oauth2/v4/store"
@@ -24,7 +25,7 @@ func TestManager(t *testing.T) {
 		_ = clientStore.Set("1", &models.Client{
 			ID:     "1",
 			Secret: "11",
-			Domain: "http://localhost",
+			Domain: "http://localhost/oauth2",
 		})
 		manager.MapClientStorage(clientStore)
 
@@ -32,7 +33,7 @@ func TestManager(t *testing.T) {
 			ClientID:    "1",
 			UserID:      "123456",
 			RedirectURI: "http://localhost/oauth2",
-			Scope:       "all",
+			Scope:       "all owner",
 		}
 
 		Convey("GetClient test", func() {
@@ -41,6 +42,24 @@ func TestManager(t *testing.T) {
 			So(cli.GetSecret(), ShouldEqual, "11")
 		})
 
+		Convey("Domain redirect uri test", func() {
+			// the redirect uri must match the domain of the client exactly by default
+			_, err := manager.GenerateAuthToken(ctx, oauth2.Code, &oauth2.TokenGenerateRequest{
+				ClientID:    "1",
+				UserID:      "123456",
+				RedirectURI: "http://localhost/other",
+			})
+			So(err, ShouldEqual, errors.ErrInvalidRedirectURI)
+
+			manager.SetValidateURIHandler(manage.DefaultValidateURI)
+			_, err = manager.GenerateAuthToken(ctx, oauth2.Code, &oauth2.TokenGenerateRequest{
+				ClientID:    "1",
+				UserID:      "123456",
+				RedirectURI: "http://localhost/other",
+			})
+			So(err, ShouldBeNil)
+		})
+
 		Convey("Token test", func() {
 			testManager(tgr, manager)
 		})
@@ -232,3 +251,257 @@ func testZeroRefreshExpirationManager(tgr *oauth2.TokenGenerateRequest, manager
 	So(tokenInfo.GetRefresh(), ShouldEqual, refreshToken)
 	So(tokenInfo.GetRefreshExpiresIn(), ShouldEqual, 0)
 }
+
+func TestRefreshTokenReuse(t *testing.T) {
+	Convey("Refresh token reuse test", t, func() {
+		manager := manage.NewDefaultManager()
+		ctx := context.Background()
+
+		manager.MustTokenStorage(store.NewMemoryTokenStore())
+		manager.MustTokenFamilyStorage(store.NewMemoryTokenFamilyStore())
+
+		clientStore := store.NewClientStore()
+		_ = clientStore.Set("1", &models.Client{
+			ID:     "1",
+			Secret: "11",
+			Domain: "http://localhost/oauth2",
+		})
+		manager.MapClientStorage(clientStore)
+
+		var events []*manage.SecurityEvent
+		manager.SetSecurityEventHandler(func(ctx context.Context, ev *manage.SecurityEvent) {
+			events = append(events, ev)
+		})
+
+		ti, err := manager.GenerateAccessToken(ctx, oauth2.PasswordCredentials, &oauth2.TokenGenerateRequest{
+			ClientID:     "1",
+			ClientSecret: "11",
+			UserID:       "123456",
+			Scope:        "all",
+		})
+		So(err, ShouldBeNil)
+		family := ti.(oauth2.FamilyTokenInfo).GetFamily()
+		So(family, ShouldNotBeEmpty)
+		refresh := ti.GetRefresh()
+
+		rti, err := manager.RefreshAccessToken(ctx, &oauth2.TokenGenerateRequest{ClientID: "1", Refresh: refresh})
+		So(err, ShouldBeNil)
+		So(rti.(oauth2.FamilyTokenInfo).GetFamily(), ShouldEqual, family)
+		So(rti.GetRefresh(), ShouldNotEqual, refresh)
+
+		Convey("reuse revokes the family", func() {
+			_, err := manager.RefreshAccessToken(ctx, &oauth2.TokenGenerateRequest{ClientID: "1", Refresh: refresh})
+			So(err, ShouldEqual, errors.ErrInvalidRefreshToken)
+			So(events, ShouldHaveLength, 1)
+			So(events[0].Type, ShouldEqual, manage.RefreshTokenReuse)
+			So(events[0].UserID, ShouldEqual, "123456")
+			So(events[0].Family, ShouldEqual, family)
+
+			_, err = manager.LoadAccessToken(ctx, rti.GetAccess())
+			So(err, ShouldNotBeNil)
+			_, err = manager.RefreshAccessToken(ctx, &oauth2.TokenGenerateRequest{ClientID: "1", Refresh: rti.GetRefresh()})
+			So(err, ShouldEqual, errors.ErrInvalidRefreshToken)
+		})
+
+		Convey("reuse within the grace period", func() {
+			manager.SetRefreshTokenCfg(&manage.RefreshingConfig{
+				IsGenerateRefresh:  true,
+				IsRemoveAccess:     true,
+				IsRemoveRefreshing: true,
+				ReuseGracePeriod:   time.Minute,
+			})
+
+			gti, err := manager.RefreshAccessToken(ctx, &oauth2.TokenGenerateRequest{ClientID: "1", Refresh: refresh})
+			So(err, ShouldBeNil)
+			So(gti.GetAccess(), ShouldEqual, rti.GetAccess())
+			So(gti.GetRefresh(), ShouldEqual, rti.GetRefresh())
+			So(events, ShouldBeEmpty)
+
+			_, err = manager.RefreshAccessToken(ctx, &oauth2.TokenGenerateRequest{ClientID: "2", Refresh: refresh})
+			So(err, ShouldEqual, errors.ErrInvalidRefreshToken)
+			So(events, ShouldHaveLength, 1)
+		})
+	})
+}
+
+func TestAuthorizationCodeReuse(t *testing.T) {
+	Convey("Authorization code reuse test", t, func() {
+		manager := manage.NewDefaultManager()
+		ctx := context.Background()
+
+		manager.MustTokenStorage(store.NewMemoryTokenStore())
+		manager.MustTokenFamilyStorage(store.NewMemoryTokenFamilyStore())
+
+		clientStore := store.NewClientStore()
+		_ = clientStore.Set("1", &models.Client{
+			ID:     "1",
+			Secret: "11",
+			Domain: "http://localhost/oauth2",
+		})
+		manager.MapClientStorage(clientStore)
+
+		var events []*manage.SecurityEvent
+		manager.SetSecurityEventHandler(func(ctx context.Context, ev *manage.SecurityEvent) {
+			events = append(events, ev)
+		})
+
+		cti, err := manager.GenerateAuthToken(ctx, oauth2.Code, &oauth2.TokenGenerateRequest{
+			ClientID:    "1",
+			UserID:      "123456",
+			RedirectURI: "http://localhost/oauth2",
+			Scope:       "all",
+		})
+		So(err, ShouldBeNil)
+
+		atParams := &oauth2.TokenGenerateRequest{
+			ClientID:     "1",
+			ClientSecret: "11",
+			RedirectURI:  "http://localhost/oauth2",
+			Code:         cti.GetCode(),
+		}
+		ati, err := manager.GenerateAccessToken(ctx, oauth2.AuthorizationCode, atParams)
+		So(err, ShouldBeNil)
+
+		// the tokens rotated from the issued tokens are revoked as well
+		rti, err := manager.RefreshAccessToken(ctx, &oauth2.TokenGenerateRequest{ClientID: "1", Refresh: ati.GetRefresh()})
+		So(err, ShouldBeNil)
+
+		_, err = manager.GenerateAccessToken(ctx, oauth2.AuthorizationCode, atParams)
+		So(err, ShouldEqual, errors.ErrInvalidAuthorizeCode)
+		So(events, ShouldHaveLength, 1)
+		So(events[0].Type, ShouldEqual, manage.AuthorizationCodeReuse)
+		So(events[0].UserID, ShouldEqual, "123456")
+
+		_, err = manager.LoadAccessToken(ctx, rti.GetAccess())
+		So(err, ShouldNotBeNil)
+		_, err = manager.LoadRefreshToken(ctx, rti.GetRefresh())
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestConsent(t *testing.T) {
+	Convey("Consent test", t, func() {
+		manager := manage.NewDefaultManager()
+		ctx := context.Background()
+		manager.MustConsentStorage(store.NewMemoryConsentStore())
+
+		ci, err := manager.GrantConsent(ctx, "123456", "1", "read")
+		So(err, ShouldBeNil)
+		So(ci.GetScope(), ShouldEqual, "read")
+
+		ci, err = manager.GrantConsent(ctx, "123456", "1", "write read")
+		So(err, ShouldBeNil)
+		So(ci.GetScope(), ShouldEqual, "read write")
+
+		cis, err := manager.ListConsents(ctx, "123456")
+		So(err, ShouldBeNil)
+		So(cis, ShouldHaveLength, 1)
+
+		Convey("expired consent", func() {
+			manager.SetConsentExp(time.Millisecond)
+			_, err := manager.GrantConsent(ctx, "123456", "2", "read")
+			So(err, ShouldBeNil)
+			time.Sleep(time.Millisecond * 5)
+
+			ci, err := manager.LoadConsent(ctx, "123456", "2")
+			So(err, ShouldBeNil)
+			So(ci, ShouldBeNil)
+		})
+
+		Convey("revoked consent", func() {
+			So(manager.RevokeConsent(ctx, "123456", "1"), ShouldBeNil)
+			ci, err := manager.LoadConsent(ctx, "123456", "1")
+			So(err, ShouldBeNil)
+			So(ci, ShouldBeNil)
+		})
+	})
+}
+
+func TestAuthorizationDetails(t *testing.T) {
+	Convey("Authorization details test", t, func() {
+		manager := manage.NewDefaultManager()
+		ctx := context.Background()
+
+		manager.MustTokenStorage(store.NewMemoryTokenStore())
+
+		clientStore := store.NewClientStore()
+		_ = clientStore.Set("1", &models.Client{
+			ID:     "1",
+			Secret: "11",
+			Domain: "http://localhost/oauth2",
+		})
+		manager.MapClientStorage(clientStore)
+
+		details, err := oauth2.ParseAuthorizationDetails(`[
+			{"type": "payment_initiation", "instructedAmount": {"currency": "EUR", "amount": "100.00"}, "creditorAccount": {"iban": "DE02100100109307118603"}},
+			{"type": "account_information", "actions": ["list_accounts"]}
+		]`)
+		So(err, ShouldBeNil)
+
+		cti, err := manager.GenerateAuthToken(ctx, oauth2.Code, &oauth2.TokenGenerateRequest{
+			ClientID:             "1",
+			UserID:               "123456",
+			RedirectURI:          "http://localhost/oauth2",
+			AuthorizationDetails: details,
+		})
+		So(err, ShouldBeNil)
+
+		// the access token is requested for one of the granted authorization details
+		ati, err := manager.GenerateAccessToken(ctx, oauth2.AuthorizationCode, &oauth2.TokenGenerateRequest{
+			ClientID:             "1",
+			ClientSecret:         "11",
+			RedirectURI:          "http://localhost/oauth2",
+			Code:                 cti.GetCode(),
+			AuthorizationDetails: details[:1],
+		})
+		So(err, ShouldBeNil)
+		So(ati.(oauth2.AuthorizationDetailsTokenInfo).GetAuthorizationDetails().Types(), ShouldResemble, []string{"payment_initiation"})
+
+		rti, err := manager.RefreshAccessToken(ctx, &oauth2.TokenGenerateRequest{ClientID: "1", Refresh: ati.GetRefresh()})
+		So(err, ShouldBeNil)
+		So(rti.(oauth2.AuthorizationDetailsTokenInfo).GetAuthorizationDetails(), ShouldHaveLength, 1)
+
+		_, err = manager.RefreshAccessToken(ctx, &oauth2.TokenGenerateRequest{
+			ClientID:             "1",
+			Refresh:              rti.GetRefresh(),
+			AuthorizationDetails: details[1:],
+		})
+		So(err, ShouldEqual, errors.ErrInvalidAuthorizationDetails)
+	})
+}
+
+func TestResourceRefreshing(t *testing.T) {
+	Convey("Resource refreshing test", t, func() {
+		manager := manage.NewDefaultManager()
+		ctx := context.Background()
+
+		manager.MustTokenStorage(store.NewMemoryTokenStore())
+
+		clientStore := store.NewClientStore()
+		_ = clientStore.Set("1", &models.Client{
+			ID:     "1",
+			Secret: "11",
+			Domain: "http://localhost/oauth2",
+		})
+		manager.MapClientStorage(clientStore)
+
+		ti, err := manager.GenerateAccessToken(ctx, oauth2.PasswordCredentials, &oauth2.TokenGenerateRequest{
+			ClientID:     "1",
+			ClientSecret: "11",
+			UserID:       "123456",
+		})
+		So(err, ShouldBeNil)
+
+		// the refresh token granted without resources can't be refreshed for a resource
+		_, err = manager.RefreshAccessToken(ctx, &oauth2.TokenGenerateRequest{
+			ClientID: "1",
+			Refresh:  ti.GetRefresh(),
+			Resource: []string{"https://admin.example.com"},
+		})
+		So(err, ShouldEqual, errors.ErrInvalidTarget)
+
+		rti, err := manager.RefreshAccessToken(ctx, &oauth2.TokenGenerateRequest{ClientID: "1", Refresh: ti.GetRefresh()})
+		So(err, ShouldBeNil)
+		So(rti.(oauth2.AudienceTokenInfo).GetAudience(), ShouldBeEmpty)
+	})
+}
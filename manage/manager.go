@@ -17,6 +17,7 @@ func NewDefaultManager() *Manager {
 	// default implementation
 	m.MapAuthorizeGenerate(generates.NewAuthorizeGenerate())
 	m.MapAccessGenerate(generates.NewAccessGenerate())
+	m.MapDeviceCodeGenerate(generates.NewDeviceCodeGenerate())
 
 	return m
 }
@@ -25,21 +26,33 @@ func NewDefaultManager() *Manager {
 func NewManager() *Manager {
 	return &Manager{
 		gtcfg:       make(map[oauth2.GrantType]*Config),
-		validateURI: DefaultValidateURI,
+		validateURI: StrictValidateURI,
 	}
 }
 
 // Manager provide authorization management
 type Manager struct {
-	codeExp           time.Duration
-	gtcfg             map[oauth2.GrantType]*Config
-	rcfg              *RefreshingConfig
-	validateURI       ValidateURIHandler
-	extractExtension  ExtractExtensionHandler
-	authorizeGenerate oauth2.AuthorizeGenerate
-	accessGenerate    oauth2.AccessGenerate
-	tokenStore        oauth2.TokenStore
-	clientStore       oauth2.ClientStore
+	codeExp            time.Duration
+	deviceCodeExp      time.Duration
+	deviceCodeInterval time.Duration
+	pushedRequestExp   time.Duration
+	consentExp         time.Duration
+	gtcfg              map[oauth2.GrantType]*Config
+	rcfg               *RefreshingConfig
+	validateURI        ValidateURIHandler
+	matchScope         oauth2.ScopeMatcher
+	extractExtension   ExtractExtensionHandler
+	authorizeGenerate  oauth2.AuthorizeGenerate
+	accessGenerate     oauth2.AccessGenerate
+	idTokenGenerate    oauth2.IDTokenGenerate
+	deviceCodeGenerate oauth2.DeviceCodeGenerate
+	tokenStore         oauth2.TokenStore
+	clientStore        oauth2.ClientStore
+	deviceCodeStore    oauth2.DeviceCodeStore
+	pushedRequestStore oauth2.PushedRequestStore
+	tokenFamilyStore   oauth2.TokenFamilyStore
+	consentStore       oauth2.ConsentStore
+	securityEvent      SecurityEventHandler
 }
 
 // get grant type config
@@ -56,8 +69,14 @@ func (m *Manager) grantConfig(gt oauth2.GrantType) *Config {
 		return DefaultPasswordTokenCfg
 	case oauth2.ClientCredentials:
 		return DefaultClientTokenCfg
-	}
-	return &Config{}
+	case oauth2.DeviceCode:
+		return DefaultDeviceCodeTokenCfg
+	case oauth2.TokenExchange:
+		return DefaultTokenExchangeTokenCfg
+	case oauth2.JWTBearer:
+		return DefaultJWTBearerTokenCfg
+	}
+	return DefaultGrantTokenCfg
 }
 
 // SetAuthorizeCodeExp set the authorization code expiration time
@@ -85,16 +104,63 @@ func (m *Manager) SetClientTokenCfg(cfg *Config) {
 	m.gtcfg[oauth2.ClientCredentials] = cfg
 }
 
+// SetDeviceCodeExp set the device code expiration time
+func (m *Manager) SetDeviceCodeExp(exp time.Duration) {
+	m.deviceCodeExp = exp
+}
+
+// SetDeviceCodeInterval set the minimum polling interval of the device code
+func (m *Manager) SetDeviceCodeInterval(interval time.Duration) {
+	m.deviceCodeInterval = interval
+}
+
+// SetPushedRequestExp set the expiration time of the pushed authorization request
+func (m *Manager) SetPushedRequestExp(exp time.Duration) {
+	m.pushedRequestExp = exp
+}
+
+// SetConsentExp set the lifetime of the user consents, 0 means they don't expire
+func (m *Manager) SetConsentExp(exp time.Duration) {
+	m.consentExp = exp
+}
+
+// SetDeviceCodeTokenCfg set the device authorization grant token config
+func (m *Manager) SetDeviceCodeTokenCfg(cfg *Config) {
+	m.gtcfg[oauth2.DeviceCode] = cfg
+}
+
+// SetTokenExchangeTokenCfg set the token exchange grant token config
+func (m *Manager) SetTokenExchangeTokenCfg(cfg *Config) {
+	m.gtcfg[oauth2.TokenExchange] = cfg
+}
+
+// SetJWTBearerTokenCfg set the jwt bearer grant token config
+func (m *Manager) SetJWTBearerTokenCfg(cfg *Config) {
+	m.gtcfg[oauth2.JWTBearer] = cfg
+}
+
+// SetGrantTokenCfg set the token config of the grant type, e.g. a custom grant type
+func (m *Manager) SetGrantTokenCfg(gt oauth2.GrantType, cfg *Config) {
+	m.gtcfg[gt] = cfg
+}
+
 // SetRefreshTokenCfg set the refreshing token config
 func (m *Manager) SetRefreshTokenCfg(cfg *RefreshingConfig) {
 	m.rcfg = cfg
 }
 
-// SetValidateURIHandler set the validates that RedirectURI is contained in baseURI
+// SetValidateURIHandler set the validates that RedirectURI matches a registered redirect uri or the domain of the client,
+// StrictValidateURI is used by default, DefaultValidateURI allows the redirect uri contained in the domain instead
 func (m *Manager) SetValidateURIHandler(handler ValidateURIHandler) {
 	m.validateURI = handler
 }
 
+// SetScopeMatcher set the matcher of the granted and requested scope tokens,
+// e.g. oauth2.HierarchicalScopes or the Match of the server scope registry, the scope tokens only match the same one by default
+func (m *Manager) SetScopeMatcher(match oauth2.ScopeMatcher) {
+	m.matchScope = match
+}
+
 // SetExtractExtensionHandler set the token extension extractor
 func (m *Manager) SetExtractExtensionHandler(handler ExtractExtensionHandler) {
 	m.extractExtension = handler
@@ -110,6 +176,17 @@ func (m *Manager) MapAccessGenerate(gen oauth2.AccessGenerate) {
 	m.accessGenerate = gen
 }
 
+// MapIDTokenGenerate mapping the OpenID Connect id token generate interface,
+// the id token is generated when the openid scope is granted to a user
+func (m *Manager) MapIDTokenGenerate(gen oauth2.IDTokenGenerate) {
+	m.idTokenGenerate = gen
+}
+
+// MapDeviceCodeGenerate mapping the device code and user code generate interface
+func (m *Manager) MapDeviceCodeGenerate(gen oauth2.DeviceCodeGenerate) {
+	m.deviceCodeGenerate = gen
+}
+
 // MapClientStorage mapping the client store interface
 func (m *Manager) MapClientStorage(stor oauth2.ClientStore) {
 	m.clientStore = stor
@@ -136,6 +213,64 @@ func (m *Manager) MustTokenStorage(stor oauth2.TokenStore, err error) {
 	m.tokenStore = stor
 }
 
+// MapDeviceCodeStorage mapping the device authorization store interface
+func (m *Manager) MapDeviceCodeStorage(stor oauth2.DeviceCodeStore) {
+	m.deviceCodeStore = stor
+}
+
+// MustDeviceCodeStorage mandatory mapping the device authorization store interface
+func (m *Manager) MustDeviceCodeStorage(stor oauth2.DeviceCodeStore, err error) {
+	if err != nil {
+		panic(err)
+	}
+	m.deviceCodeStore = stor
+}
+
+// MapPushedRequestStorage mapping the pushed authorization request store interface
+func (m *Manager) MapPushedRequestStorage(stor oauth2.PushedRequestStore) {
+	m.pushedRequestStore = stor
+}
+
+// MustPushedRequestStorage mandatory mapping the pushed authorization request store interface
+func (m *Manager) MustPushedRequestStorage(stor oauth2.PushedRequestStore, err error) {
+	if err != nil {
+		panic(err)
+	}
+	m.pushedRequestStore = stor
+}
+
+// MapTokenFamilyStorage mapping the token family store interface, the reuse of
+// the redeemed authorization codes and rotated refresh tokens is detected when it is mapped
+func (m *Manager) MapTokenFamilyStorage(stor oauth2.TokenFamilyStore) {
+	m.tokenFamilyStore = stor
+}
+
+// MustTokenFamilyStorage mandatory mapping the token family store interface
+func (m *Manager) MustTokenFamilyStorage(stor oauth2.TokenFamilyStore, err error) {
+	if err != nil {
+		panic(err)
+	}
+	m.tokenFamilyStore = stor
+}
+
+// MapConsentStorage mapping the consent store interface
+func (m *Manager) MapConsentStorage(stor oauth2.ConsentStore) {
+	m.consentStore = stor
+}
+
+// MustConsentStorage mandatory mapping the consent store interface
+func (m *Manager) MustConsentStorage(stor oauth2.ConsentStore, err error) {
+	if err != nil {
+		panic(err)
+	}
+	m.consentStore = stor
+}
+
+// SetSecurityEventHandler set the handler of the detected security events
+func (m *Manager) SetSecurityEventHandler(handler SecurityEventHandler) {
+	m.securityEvent = handler
+}
+
 // GetClient get the client information
 func (m *Manager) GetClient(ctx context.Context, clientID string) (cli oauth2.ClientInfo, err error) {
 	cli, err = m.clientStore.GetByID(ctx, clientID)
@@ -143,6 +278,8 @@ func (m *Manager) GetClient(ctx context.Context, clientID string) (cli oauth2.Cl
 		return
 	} else if cli == nil {
 		err = errors.ErrInvalidClient
+	} else if dcli, ok := cli.(oauth2.DisabledClientInfo); ok && dcli.IsDisabled() {
+		cli, err = nil, errors.ErrInvalidClient
 	}
 	return
 }
@@ -153,10 +290,18 @@ func (m *Manager) GenerateAuthToken(ctx context.Context, rt oauth2.ResponseType,
 	if err != nil {
 		return nil, err
 	} else if tgr.RedirectURI != "" {
-		if err := m.validateURI(cli.GetDomain(), tgr.RedirectURI); err != nil {
+		if err := m.validateClientURI(cli, tgr.RedirectURI); err != nil {
 			return nil, err
 		}
 	}
+	if !allowedResponseType(cli, rt) {
+		return nil, errors.ErrUnauthorizedClient
+	} else if !allowedScope(cli, tgr.Scope, m.matchScope) {
+		return nil, errors.ErrInvalidScope
+	} else if !allowedResource(cli, tgr.Resource) {
+		return nil, errors.ErrInvalidTarget
+	}
+	clientAccessExp, _ := clientTokenExp(cli)
 
 	ti := models.NewToken()
 	if m.extractExtension != nil {
@@ -166,6 +311,11 @@ func (m *Manager) GenerateAuthToken(ctx context.Context, rt oauth2.ResponseType,
 	ti.SetUserID(tgr.UserID)
 	ti.SetRedirectURI(tgr.RedirectURI)
 	ti.SetScope(tgr.Scope)
+	ti.SetNonce(tgr.Nonce)
+	ti.SetAuthTime(tgr.AuthTime)
+	// the resources of the authorization request are the audience of the issued tokens
+	ti.SetAudience(tgr.Resource)
+	ti.SetAuthorizationDetails(tgr.AuthorizationDetails)
 
 	createAt := time.Now()
 	td := &oauth2.GenerateBasic{
@@ -185,6 +335,8 @@ func (m *Manager) GenerateAuthToken(ctx context.Context, rt oauth2.ResponseType,
 		ti.SetCodeExpiresIn(codeExp)
 		if exp := tgr.AccessTokenExp; exp > 0 {
 			ti.SetAccessExpiresIn(exp)
+		} else if clientAccessExp > 0 {
+			ti.SetAccessExpiresIn(clientAccessExp)
 		}
 		if tgr.CodeChallenge != "" {
 			ti.SetCodeChallenge(tgr.CodeChallenge)
@@ -202,6 +354,8 @@ func (m *Manager) GenerateAuthToken(ctx context.Context, rt oauth2.ResponseType,
 		aexp := icfg.AccessTokenExp
 		if exp := tgr.AccessTokenExp; exp > 0 {
 			aexp = exp
+		} else if clientAccessExp > 0 {
+			aexp = clientAccessExp
 		}
 		ti.SetAccessCreateAt(createAt)
 		ti.SetAccessExpiresIn(aexp)
@@ -220,6 +374,10 @@ func (m *Manager) GenerateAuthToken(ctx context.Context, rt oauth2.ResponseType,
 		if rv != "" {
 			ti.SetRefresh(rv)
 		}
+
+		if err := m.generateIDToken(ctx, td, ""); err != nil {
+			return nil, err
+		}
 	}
 
 	err = m.tokenStore.Create(ctx, ti)
@@ -249,6 +407,13 @@ func (m *Manager) delAuthorizationCode(ctx context.Context, code string) error {
 // get and delete authorization code data
 func (m *Manager) getAndDelAuthorizationCode(ctx context.Context, tgr *oauth2.TokenGenerateRequest) (oauth2.TokenInfo, error) {
 	code := tgr.Code
+	// the redeemed code is replayed
+	if family, _, err := m.retiredFamily(ctx, code); err != nil {
+		return nil, err
+	} else if family != "" {
+		return nil, m.reuseAuthorizationCode(ctx, tgr, family)
+	}
+
 	ti, err := m.getAuthorizationCode(ctx, code)
 	if err != nil {
 		return nil, err
@@ -293,21 +458,25 @@ func (m *Manager) GenerateAccessToken(ctx context.Context, gt oauth2.GrantType,
 	if err != nil {
 		return nil, err
 	}
-	if cliPass, ok := cli.(oauth2.ClientPasswordVerifier); ok {
-		if !cliPass.VerifyPassword(tgr.ClientSecret) {
-			return nil, errors.ErrInvalidClient
-		}
-	} else if len(cli.GetSecret()) > 0 && tgr.ClientSecret != cli.GetSecret() {
+	// the secret isn't compared when the client is already authenticated by another method
+	if tgr.ClientAuthMethod == "" && !validClientSecret(cli, tgr.ClientSecret) {
 		return nil, errors.ErrInvalidClient
 	}
 	if tgr.RedirectURI != "" {
-		if err := m.validateURI(cli.GetDomain(), tgr.RedirectURI); err != nil {
+		if err := m.validateClientURI(cli, tgr.RedirectURI); err != nil {
 			return nil, err
 		}
 	}
 
 	if gt == oauth2.ClientCredentials && cli.IsPublic() == true {
 		return nil, errors.ErrInvalidClient
+	} else if !allowedGrantType(cli, gt) {
+		return nil, errors.ErrUnauthorizedClient
+	} else if !allowedScope(cli, tgr.Scope, m.matchScope) {
+		return nil, errors.ErrInvalidScope
+	} else if !allowedResource(cli, tgr.Resource) || !allowedResource(cli, tgr.Audience) {
+		// the audience decided by the grant is restricted to the allowed resources as well
+		return nil, errors.ErrInvalidTarget
 	}
 
 	var extension url.Values
@@ -322,12 +491,35 @@ func (m *Manager) GenerateAccessToken(ctx context.Context, gt oauth2.GrantType,
 		}
 		tgr.UserID = ti.GetUserID()
 		tgr.Scope = ti.GetScope()
+		if ati, ok := ti.(oauth2.AudienceTokenInfo); ok {
+			tgr.Resource, err = narrowResource(ati.GetAudience(), tgr.Resource)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if dti, ok := ti.(oauth2.AuthorizationDetailsTokenInfo); ok {
+			tgr.AuthorizationDetails, err = narrowAuthorizationDetails(dti.GetAuthorizationDetails(), tgr.AuthorizationDetails)
+			if err != nil {
+				return nil, err
+			}
+		}
 		if exp := ti.GetAccessExpiresIn(); exp > 0 {
 			tgr.AccessTokenExp = exp
 		}
 		if eti, ok := ti.(oauth2.ExtendableTokenInfo); ok {
 			extension = eti.GetExtension()
 		}
+		if oti, ok := ti.(oauth2.OpenIDTokenInfo); ok {
+			tgr.Nonce = oti.GetNonce()
+			tgr.AuthTime = oti.GetAuthTime()
+		}
+	} else if gt == oauth2.DeviceCode {
+		di, err := m.pollDeviceCode(ctx, tgr)
+		if err != nil {
+			return nil, err
+		}
+		tgr.UserID = di.GetUserID()
+		tgr.Scope = di.GetScope()
 	}
 
 	ti := models.NewToken()
@@ -339,20 +531,46 @@ func (m *Manager) GenerateAccessToken(ctx context.Context, gt oauth2.GrantType,
 	ti.SetUserID(tgr.UserID)
 	ti.SetRedirectURI(tgr.RedirectURI)
 	ti.SetScope(tgr.Scope)
+	ti.SetNonce(tgr.Nonce)
+	// the token is issued to the requested resources unless the audience is decided by the grant
+	if len(tgr.Audience) > 0 {
+		ti.SetAudience(tgr.Audience)
+	} else {
+		ti.SetAudience(tgr.Resource)
+	}
+	ti.SetActor(tgr.Actor)
+	ti.SetConfirmation(tgr.Confirmation)
+	ti.SetAuthorizationDetails(tgr.AuthorizationDetails)
 
 	createAt := time.Now()
 	ti.SetAccessCreateAt(createAt)
 
+	// the resource owner is authenticated by the password grant itself
+	if gt == oauth2.PasswordCredentials && tgr.AuthTime.IsZero() {
+		tgr.AuthTime = createAt
+	}
+	ti.SetAuthTime(tgr.AuthTime)
+
 	// set access token expires
 	gcfg := m.grantConfig(gt)
+	cexp, crexp := clientTokenExp(cli)
 	aexp := gcfg.AccessTokenExp
 	if exp := tgr.AccessTokenExp; exp > 0 {
 		aexp = exp
+	} else if cexp > 0 {
+		aexp = cexp
 	}
 	ti.SetAccessExpiresIn(aexp)
-	if gcfg.IsGenerateRefresh {
+
+	// the refresh token is only issued when the client allows the refreshing grant
+	isGenRefresh := gcfg.IsGenerateRefresh && allowedGrantType(cli, oauth2.Refreshing)
+	if isGenRefresh {
+		rexp := gcfg.RefreshTokenExp
+		if crexp > 0 {
+			rexp = crexp
+		}
 		ti.SetRefreshCreateAt(createAt)
-		ti.SetRefreshExpiresIn(gcfg.RefreshTokenExp)
+		ti.SetRefreshExpiresIn(rexp)
 	}
 
 	td := &oauth2.GenerateBasic{
@@ -363,7 +581,7 @@ func (m *Manager) GenerateAccessToken(ctx context.Context, gt oauth2.GrantType,
 		Request:   tgr.Request,
 	}
 
-	av, rv, err := m.accessGenerate.Token(ctx, td, gcfg.IsGenerateRefresh)
+	av, rv, err := m.accessGenerate.Token(ctx, td, isGenRefresh)
 	if err != nil {
 		return nil, err
 	}
@@ -373,6 +591,22 @@ func (m *Manager) GenerateAccessToken(ctx context.Context, gt oauth2.GrantType,
 		ti.SetRefresh(rv)
 	}
 
+	// the redeemed code is retired from the family of the issued tokens to revoke them on its replay
+	if rv != "" || gt == oauth2.AuthorizationCode {
+		if err := m.updateFamily(ctx, ti); err != nil {
+			return nil, err
+		}
+	}
+	if gt == oauth2.AuthorizationCode {
+		if err := m.retireToken(ctx, ti, tgr.Code); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.generateIDToken(ctx, td, tgr.Code); err != nil {
+		return nil, err
+	}
+
 	err = m.tokenStore.Create(ctx, ti)
 	if err != nil {
 		return nil, err
@@ -381,8 +615,41 @@ func (m *Manager) GenerateAccessToken(ctx context.Context, gt oauth2.GrantType,
 	return ti, nil
 }
 
+// generate the id token when the openid scope is granted to a user
+func (m *Manager) generateIDToken(ctx context.Context, td *oauth2.GenerateBasic, code string) error {
+	ti, ok := td.TokenInfo.(oauth2.OpenIDTokenInfo)
+	if !ok {
+		return nil
+	}
+
+	ti.SetIDToken("")
+	if m.idTokenGenerate == nil || td.UserID == "" || !hasScope(ti.GetScope(), ScopeOpenID) {
+		return nil
+	}
+
+	idToken, err := m.idTokenGenerate.Token(ctx, td, code)
+	if err != nil {
+		return err
+	}
+	ti.SetIDToken(idToken)
+	return nil
+}
+
 // RefreshAccessToken refreshing an access token
 func (m *Manager) RefreshAccessToken(ctx context.Context, tgr *oauth2.TokenGenerateRequest) (oauth2.TokenInfo, error) {
+	rcfg := DefaultRefreshTokenCfg
+	if v := m.rcfg; v != nil {
+		rcfg = v
+	}
+
+	// the refresh token was already rotated
+	family, retiredAt, err := m.retiredFamily(ctx, tgr.Refresh)
+	if err != nil {
+		return nil, err
+	} else if family != "" {
+		return m.reuseRefreshToken(ctx, tgr, family, retiredAt, rcfg.ReuseGracePeriod)
+	}
+
 	ti, err := m.LoadRefreshToken(ctx, tgr.Refresh)
 	if err != nil {
 		return nil, err
@@ -391,6 +658,32 @@ func (m *Manager) RefreshAccessToken(ctx context.Context, tgr *oauth2.TokenGener
 	cli, err := m.GetClient(ctx, ti.GetClientID())
 	if err != nil {
 		return nil, err
+	} else if !allowedGrantType(cli, oauth2.Refreshing) {
+		return nil, errors.ErrUnauthorizedClient
+	} else if !allowedScope(cli, tgr.Scope, m.matchScope) {
+		return nil, errors.ErrInvalidScope
+	} else if !impliesScope(ti.GetScope(), tgr.Scope, m.matchScope) {
+		// the scope is narrowed within the scope granted to the refresh token (RFC 6749 section 6)
+		return nil, errors.ErrInvalidScope
+	} else if !allowedResource(cli, tgr.Resource) {
+		return nil, errors.ErrInvalidTarget
+	}
+
+	// and the audience within the resources granted to the refresh token
+	if ati, ok := ti.(oauth2.AudienceTokenInfo); ok {
+		aud, err := narrowResource(ati.GetAudience(), tgr.Resource)
+		if err != nil {
+			return nil, err
+		}
+		ati.SetAudience(aud)
+	}
+	// and the authorization details within the authorization details granted to the refresh token
+	if dti, ok := ti.(oauth2.AuthorizationDetailsTokenInfo); ok {
+		details, err := narrowAuthorizationDetails(dti.GetAuthorizationDetails(), tgr.AuthorizationDetails)
+		if err != nil {
+			return nil, err
+		}
+		dti.SetAuthorizationDetails(details)
 	}
 
 	oldAccess, oldRefresh := ti.GetAccess(), ti.GetRefresh()
@@ -403,17 +696,17 @@ func (m *Manager) RefreshAccessToken(ctx context.Context, tgr *oauth2.TokenGener
 		Request:   tgr.Request,
 	}
 
-	rcfg := DefaultRefreshTokenCfg
-	if v := m.rcfg; v != nil {
-		rcfg = v
-	}
-
+	cexp, crexp := clientTokenExp(cli)
 	ti.SetAccessCreateAt(td.CreateAt)
-	if v := rcfg.AccessTokenExp; v > 0 {
+	if cexp > 0 {
+		ti.SetAccessExpiresIn(cexp)
+	} else if v := rcfg.AccessTokenExp; v > 0 {
 		ti.SetAccessExpiresIn(v)
 	}
 
-	if v := rcfg.RefreshTokenExp; v > 0 {
+	if crexp > 0 {
+		ti.SetRefreshExpiresIn(crexp)
+	} else if v := rcfg.RefreshTokenExp; v > 0 {
 		ti.SetRefreshExpiresIn(v)
 	}
 
@@ -425,6 +718,11 @@ func (m *Manager) RefreshAccessToken(ctx context.Context, tgr *oauth2.TokenGener
 		ti.SetScope(scope)
 	}
 
+	// the refreshed token is bound to the key of the refresh request
+	if bti, ok := ti.(oauth2.BoundTokenInfo); ok {
+		bti.SetConfirmation(tgr.Confirmation)
+	}
+
 	tv, rv, err := m.accessGenerate.Token(ctx, td, rcfg.IsGenerateRefresh)
 	if err != nil {
 		return nil, err
@@ -433,6 +731,20 @@ func (m *Manager) RefreshAccessToken(ctx context.Context, tgr *oauth2.TokenGener
 	ti.SetAccess(tv)
 	if rv != "" {
 		ti.SetRefresh(rv)
+		// the rotated refresh token is retired from the family to detect its reuse
+		if err := m.updateFamily(ctx, ti); err != nil {
+			return nil, err
+		} else if err := m.retireToken(ctx, ti, oldRefresh); err != nil {
+			return nil, err
+		}
+	}
+
+	// the id token reissued on refresh carries no nonce (OpenID Connect Core section 12.2)
+	if oti, ok := ti.(oauth2.OpenIDTokenInfo); ok {
+		oti.SetNonce("")
+	}
+	if err := m.generateIDToken(ctx, td, ""); err != nil {
+		return nil, err
 	}
 
 	if err := m.tokenStore.Create(ctx, ti); err != nil {
@@ -0,0 +1,75 @@
+package manage
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
+)
+
+// PushAuthorizeRequest store the parameters of the authorization request and generate the request uri,
+// the reference of the request uri is generated by the authorize code generator
+func (m *Manager) PushAuthorizeRequest(ctx context.Context, clientID string, params url.Values) (oauth2.PushedRequestInfo, error) {
+	if m.pushedRequestStore == nil {
+		return nil, errors.ErrInvalidPushedRequest
+	}
+
+	cli, err := m.GetClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	createAt := time.Now()
+	ref, err := m.authorizeGenerate.Token(ctx, &oauth2.GenerateBasic{
+		Client:   cli,
+		CreateAt: createAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exp := m.pushedRequestExp
+	if exp == 0 {
+		exp = DefaultPushedRequestExp
+	}
+
+	pi := models.NewPushedRequest()
+	pi.SetRequestURI(oauth2.RequestURIPrefix + ref)
+	pi.SetClientID(clientID)
+	pi.SetParams(params)
+	pi.SetCreateAt(createAt)
+	pi.SetExpiresIn(exp)
+
+	err = m.pushedRequestStore.Create(ctx, pi)
+	if err != nil {
+		return nil, err
+	}
+	return pi, nil
+}
+
+// LoadPushedRequest according to the request uri for the pushed authorization request of the client
+func (m *Manager) LoadPushedRequest(ctx context.Context, clientID, requestURI string) (oauth2.PushedRequestInfo, error) {
+	if m.pushedRequestStore == nil {
+		return nil, errors.ErrInvalidPushedRequest
+	}
+
+	pi, err := m.pushedRequestStore.GetByURI(ctx, requestURI)
+	if err != nil {
+		return nil, err
+	} else if pi == nil || pi.GetClientID() != clientID ||
+		pi.GetCreateAt().Add(pi.GetExpiresIn()).Before(time.Now()) {
+		return nil, errors.ErrInvalidPushedRequest
+	}
+	return pi, nil
+}
+
+// RemovePushedRequest use the request uri to delete the pushed authorization request
+func (m *Manager) RemovePushedRequest(ctx context.Context, requestURI string) error {
+	if m.pushedRequestStore == nil {
+		return nil
+	}
+	return m.pushedRequestStore.RemoveByURI(ctx, requestURI)
+}
@@ -0,0 +1,141 @@
+package manage
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/google/uuid"
+)
+
+// the expiration time of the token family, the zero time if the token doesn't expire
+func familyExp(ti oauth2.TokenInfo) time.Time {
+	if ti.GetRefresh() != "" {
+		if exp := ti.GetRefreshExpiresIn(); exp > 0 {
+			return ti.GetRefreshCreateAt().Add(exp)
+		}
+		return time.Time{}
+	}
+	if exp := ti.GetAccessExpiresIn(); exp > 0 {
+		return ti.GetAccessCreateAt().Add(exp)
+	}
+	return time.Time{}
+}
+
+// check the token is bound to the same key as the request
+func sameConfirmation(ti oauth2.TokenInfo, bound *oauth2.Confirmation) bool {
+	var cnf *oauth2.Confirmation
+	if bti, ok := ti.(oauth2.BoundTokenInfo); ok {
+		cnf = bti.GetConfirmation()
+	}
+	if cnf == nil || (cnf.JKT == "" && cnf.X5TS256 == "") {
+		return true
+	}
+	return bound != nil && bound.JKT == cnf.JKT && bound.X5TS256 == cnf.X5TS256
+}
+
+// set the token as the current token of its family, a new family is started for the token without one
+func (m *Manager) updateFamily(ctx context.Context, ti oauth2.TokenInfo) error {
+	fti, ok := ti.(oauth2.FamilyTokenInfo)
+	if m.tokenFamilyStore == nil || !ok {
+		return nil
+	}
+	if fti.GetFamily() == "" {
+		fti.SetFamily(uuid.Must(uuid.NewRandom()).String())
+	}
+	return m.tokenFamilyStore.SetCurrent(ctx, fti.GetFamily(), ti.GetAccess(), ti.GetRefresh(), familyExp(ti))
+}
+
+// retire the rotated token from the family of the token information
+func (m *Manager) retireToken(ctx context.Context, ti oauth2.TokenInfo, token string) error {
+	fti, ok := ti.(oauth2.FamilyTokenInfo)
+	if m.tokenFamilyStore == nil || !ok || fti.GetFamily() == "" || token == "" {
+		return nil
+	}
+	return m.tokenFamilyStore.Retire(ctx, fti.GetFamily(), token, familyExp(ti))
+}
+
+// get the family of the retired token, the family is empty if the token isn't retired
+func (m *Manager) retiredFamily(ctx context.Context, token string) (string, time.Time, error) {
+	if m.tokenFamilyStore == nil || token == "" {
+		return "", time.Time{}, nil
+	}
+	return m.tokenFamilyStore.GetRetired(ctx, token)
+}
+
+// delete the current tokens of the family and the family itself
+func (m *Manager) revokeFamily(ctx context.Context, family, access, refresh string) error {
+	if access != "" {
+		if err := m.tokenStore.RemoveByAccess(ctx, access); err != nil {
+			return err
+		}
+	}
+	if refresh != "" {
+		if err := m.tokenStore.RemoveByRefresh(ctx, refresh); err != nil {
+			return err
+		}
+	}
+	return m.tokenFamilyStore.RemoveFamily(ctx, family)
+}
+
+// get the current tokens of the family and the token information of them
+func (m *Manager) familyToken(ctx context.Context, family string) (access, refresh string, ti oauth2.TokenInfo, err error) {
+	access, refresh, err = m.tokenFamilyStore.GetCurrent(ctx, family)
+	if err != nil {
+		return
+	}
+	if refresh != "" {
+		ti, err = m.tokenStore.GetByRefresh(ctx, refresh)
+	} else if access != "" {
+		ti, err = m.tokenStore.GetByAccess(ctx, access)
+	}
+	return
+}
+
+// revoke the family of the reused token and report the security event
+func (m *Manager) revokeReusedFamily(ctx context.Context, ev *SecurityEvent, access, refresh string, ti oauth2.TokenInfo) error {
+	if ti != nil {
+		ev.ClientID, ev.UserID = ti.GetClientID(), ti.GetUserID()
+	}
+	if err := m.revokeFamily(ctx, ev.Family, access, refresh); err != nil {
+		return err
+	}
+	m.reportSecurityEvent(ctx, ev)
+	return nil
+}
+
+// handle the reuse of the rotated refresh token, the current token of the family is returned
+// to the same client within the grace period, otherwise the whole family is revoked
+// https://datatracker.ietf.org/doc/html/draft-ietf-oauth-security-topics#section-4.14.2
+func (m *Manager) reuseRefreshToken(ctx context.Context, tgr *oauth2.TokenGenerateRequest, family string, retiredAt time.Time, grace time.Duration) (oauth2.TokenInfo, error) {
+	access, refresh, ti, err := m.familyToken(ctx, family)
+	if err != nil {
+		return nil, err
+	}
+	if ti != nil && refresh != "" && time.Since(retiredAt) <= grace &&
+		ti.GetClientID() == tgr.ClientID && sameConfirmation(ti, tgr.Confirmation) {
+		return ti, nil
+	}
+
+	ev := &SecurityEvent{Type: RefreshTokenReuse, ClientID: tgr.ClientID, Family: family}
+	if err := m.revokeReusedFamily(ctx, ev, access, refresh, ti); err != nil {
+		return nil, err
+	}
+	return nil, errors.ErrInvalidRefreshToken
+}
+
+// handle the replay of the redeemed authorization code, the tokens issued from the code are revoked
+// https://tools.ietf.org/html/rfc6749#section-4.1.2
+func (m *Manager) reuseAuthorizationCode(ctx context.Context, tgr *oauth2.TokenGenerateRequest, family string) error {
+	access, refresh, ti, err := m.familyToken(ctx, family)
+	if err != nil {
+		return err
+	}
+
+	ev := &SecurityEvent{Type: AuthorizationCodeReuse, ClientID: tgr.ClientID, Family: family}
+	if err := m.revokeReusedFamily(ctx, ev, access, refresh, ti); err != nil {
+		return err
+	}
+	return errors.ErrInvalidAuthorizeCode
+}
@@ -2,6 +2,7 @@ package manage
 
 import (
 	"github.com/go-oauth2/oauth2/v4"
+	"net"
 	"net/url"
 	"strings"
 
@@ -14,6 +15,53 @@ type (
 	ExtractExtensionHandler func(*oauth2.TokenGenerateRequest, oauth2.ExtendableTokenInfo)
 )
 
+// ScopeOpenID the scope value of an OpenID Connect request
+const ScopeOpenID = "openid"
+
+// check whether the space-delimited scope contains the value
+func hasScope(scope, value string) bool {
+	ps, _ := oauth2.ParseScope(scope)
+	return ps.Has(value)
+}
+
+// check whether the granted scope implies all tokens of the requested scope by the scope matcher
+func impliesScope(granted, requested string, match oauth2.ScopeMatcher) bool {
+	gs, _ := oauth2.ParseScope(granted)
+	rs, err := oauth2.ParseScope(requested)
+	return err == nil && gs.ImpliesAll(rs, match)
+}
+
+// narrow the resources of the grant to the requested resources,
+// the requested resources must be within the granted resources (RFC 8707 section 2.2),
+// so no resource can be requested for the grant without resources
+func narrowResource(granted, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return granted, nil
+	} else if !oauth2.WithinAudience(granted, requested) {
+		return nil, errors.ErrInvalidTarget
+	}
+	return requested, nil
+}
+
+// narrow the authorization details of the grant to the requested authorization details,
+// the requested objects must be the granted objects (RFC 9396 section 6.1)
+func narrowAuthorizationDetails(granted, requested oauth2.AuthorizationDetails) (oauth2.AuthorizationDetails, error) {
+	if len(requested) == 0 {
+		return granted, nil
+	} else if !granted.Contains(requested) {
+		return nil, errors.ErrInvalidAuthorizationDetails
+	}
+	return requested, nil
+}
+
+// check the secret of the client
+func validClientSecret(cli oauth2.ClientInfo, secret string) bool {
+	if cliPass, ok := cli.(oauth2.ClientPasswordVerifier); ok {
+		return cliPass.VerifyPassword(secret)
+	}
+	return len(cli.GetSecret()) == 0 || secret == cli.GetSecret()
+}
+
 // DefaultValidateURI validates that redirectURI is contained in baseURI
 func DefaultValidateURI(baseURI string, redirectURI string) error {
 	base, err := url.Parse(baseURI)
@@ -30,3 +78,36 @@ func DefaultValidateURI(baseURI string, redirectURI string) error {
 	}
 	return nil
 }
+
+// StrictValidateURI validates that redirectURI exactly matches the registered baseURI,
+// only the port of the loopback redirect uri of a native app may vary (RFC 8252 section 7.3),
+// the private-use scheme redirect uris (RFC 8252 section 7.1) are matched exactly as well
+func StrictValidateURI(baseURI string, redirectURI string) error {
+	if baseURI == redirectURI {
+		return nil
+	}
+
+	base, err := url.Parse(baseURI)
+	if err != nil {
+		return err
+	}
+
+	redirect, err := url.Parse(redirectURI)
+	if err != nil {
+		return err
+	}
+	if base.Scheme == "http" && redirect.Scheme == "http" &&
+		isLoopback(base.Hostname()) && base.Hostname() == redirect.Hostname() &&
+		base.User == nil && redirect.User == nil &&
+		base.Path == redirect.Path && base.RawQuery == redirect.RawQuery &&
+		redirect.Fragment == "" {
+		return nil
+	}
+	return errors.ErrInvalidRedirectURI
+}
+
+// check the host is a loopback ip literal, the localhost name isn't a loopback address
+func isLoopback(host string) bool {
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -14,5 +14,28 @@ func TestUtil(t *testing.T) {
 			err := manage.DefaultValidateURI("http://www.example.com", "http://www.example.com/cb?code=xxx")
 			So(err, ShouldBeNil)
 		})
+
+		Convey("StrictValidateURI Test", func() {
+			for _, c := range [][2]string{
+				{"https://example.com/cb", "https://example.com/cb"},
+				{"com.example.app:/oauth2redirect", "com.example.app:/oauth2redirect"},
+				{"http://127.0.0.1/cb", "http://127.0.0.1:51004/cb"},
+				{"http://[::1]:8080/cb", "http://[::1]:51004/cb"},
+			} {
+				So(manage.StrictValidateURI(c[0], c[1]), ShouldBeNil)
+			}
+
+			for _, c := range [][2]string{
+				{"https://example.com/cb", "https://example.com/cb/other"},
+				{"https://example.com/cb", "https://example.com/cb?x=1"},
+				{"https://example.com/cb", "https://www.example.com/cb"},
+				{"https://example.com/cb", "http://example.com/cb"},
+				{"com.example.app:/oauth2redirect", "com.example.app:/other"},
+				{"http://localhost/cb", "http://localhost:51004/cb"},
+				{"http://127.0.0.1/cb", "http://127.0.0.1:51004/other"},
+			} {
+				So(manage.StrictValidateURI(c[0], c[1]), ShouldNotBeNil)
+			}
+		})
 	})
 }
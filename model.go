@@ -20,6 +20,79 @@ type (
 		VerifyPassword(string) bool
 	}
 
+	// PushedRequestClientInfo the client information of a client that must push the authorization requests
+	PushedRequestClientInfo interface {
+		ClientInfo
+		IsPushedRequestRequired() bool
+	}
+
+	// RedirectURIsClientInfo the client information with the registered redirect uris
+	RedirectURIsClientInfo interface {
+		ClientInfo
+		GetRedirectURIs() []string
+	}
+
+	// GrantTypesClientInfo the client information with the allowed grant types,
+	// all grant types are allowed when none is registered
+	GrantTypesClientInfo interface {
+		ClientInfo
+		GetGrantTypes() []GrantType
+	}
+
+	// ResponseTypesClientInfo the client information with the allowed response types,
+	// all response types are allowed when none is registered
+	ResponseTypesClientInfo interface {
+		ClientInfo
+		GetResponseTypes() []ResponseType
+	}
+
+	// ScopeClientInfo the client information with the allowed space-delimited scope,
+	// any scope is allowed when it is empty
+	ScopeClientInfo interface {
+		ClientInfo
+		GetScope() string
+	}
+
+	// ResourceClientInfo the client information with the allowed resources of the resource indicators (RFC 8707),
+	// any resource is allowed when it is empty
+	ResourceClientInfo interface {
+		ClientInfo
+		GetResources() []string
+	}
+
+	// AuthMethodClientInfo the client information with the registered token endpoint authentication method
+	AuthMethodClientInfo interface {
+		ClientInfo
+		GetTokenEndpointAuthMethod() ClientAuthMethod
+	}
+
+	// TokenExpClientInfo the client information with the token lifetimes of the client,
+	// the zero lifetime keeps the lifetime of the grant type
+	TokenExpClientInfo interface {
+		ClientInfo
+		GetAccessTokenExp() time.Duration
+		GetRefreshTokenExp() time.Duration
+	}
+
+	// PKCEClientInfo the client information of a client that must use PKCE
+	PKCEClientInfo interface {
+		ClientInfo
+		IsPKCERequired() bool
+	}
+
+	// DisabledClientInfo the client information of a client that can be disabled
+	DisabledClientInfo interface {
+		ClientInfo
+		IsDisabled() bool
+	}
+
+	// TLSClientInfo the client information of a client authenticated by the PKI mutual TLS method,
+	// the subject distinguished name of its certificate is registered (RFC 8705 section 2.1.2)
+	TLSClientInfo interface {
+		ClientInfo
+		GetTLSClientAuthSubjectDN() string
+	}
+
 	// TokenInfo the token information model interface
 	TokenInfo interface {
 		New() TokenInfo
@@ -64,4 +137,126 @@ type (
 		GetExtension() url.Values
 		SetExtension(url.Values)
 	}
+
+	// DeviceCodeInfo the device authorization information model interface
+	DeviceCodeInfo interface {
+		New() DeviceCodeInfo
+
+		GetClientID() string
+		SetClientID(string)
+		GetUserID() string
+		SetUserID(string)
+		GetScope() string
+		SetScope(string)
+
+		GetDeviceCode() string
+		SetDeviceCode(string)
+		GetUserCode() string
+		SetUserCode(string)
+		GetCreateAt() time.Time
+		SetCreateAt(time.Time)
+		GetExpiresIn() time.Duration
+		SetExpiresIn(time.Duration)
+		GetInterval() time.Duration
+		SetInterval(time.Duration)
+		GetLastPollAt() time.Time
+		SetLastPollAt(time.Time)
+		GetStatus() DeviceCodeStatus
+		SetStatus(DeviceCodeStatus)
+	}
+
+	// PushedRequestInfo the pushed authorization request information model interface
+	PushedRequestInfo interface {
+		New() PushedRequestInfo
+
+		GetRequestURI() string
+		SetRequestURI(string)
+		GetClientID() string
+		SetClientID(string)
+		GetParams() url.Values
+		SetParams(url.Values)
+		GetCreateAt() time.Time
+		SetCreateAt(time.Time)
+		GetExpiresIn() time.Duration
+		SetExpiresIn(time.Duration)
+	}
+
+	// ConsentInfo the consent information model interface, the scope the user granted to the client
+	ConsentInfo interface {
+		New() ConsentInfo
+
+		GetUserID() string
+		SetUserID(string)
+		GetClientID() string
+		SetClientID(string)
+		GetScope() string
+		SetScope(string)
+		GetCreateAt() time.Time
+		SetCreateAt(time.Time)
+		GetExpiresIn() time.Duration
+		SetExpiresIn(time.Duration)
+	}
+
+	// OpenIDTokenInfo the token information of an OpenID Connect authentication
+	OpenIDTokenInfo interface {
+		TokenInfo
+		GetNonce() string
+		SetNonce(string)
+		GetAuthTime() time.Time
+		SetAuthTime(time.Time)
+		GetIDToken() string
+		SetIDToken(string)
+	}
+
+	// AudienceTokenInfo the token information of a token issued to the intended audience
+	AudienceTokenInfo interface {
+		TokenInfo
+		GetAudience() []string
+		SetAudience([]string)
+	}
+
+	// BoundTokenInfo the token information of a sender-constrained token
+	BoundTokenInfo interface {
+		TokenInfo
+		GetConfirmation() *Confirmation
+		SetConfirmation(*Confirmation)
+	}
+
+	// FamilyTokenInfo the token information of a token belonging to a token family,
+	// the family links the tokens issued from the same authorization code or refresh token
+	FamilyTokenInfo interface {
+		TokenInfo
+		GetFamily() string
+		SetFamily(string)
+	}
+
+	// DelegatedTokenInfo the token information of a token issued to an actor on behalf of the subject
+	DelegatedTokenInfo interface {
+		TokenInfo
+		GetActor() *Actor
+		SetActor(*Actor)
+	}
+
+	// AuthorizationDetailsTokenInfo the token information of a token granted for the authorization details
+	// of a rich authorization request
+	AuthorizationDetailsTokenInfo interface {
+		TokenInfo
+		GetAuthorizationDetails() AuthorizationDetails
+		SetAuthorizationDetails(AuthorizationDetails)
+	}
 )
+
+// Confirmation the key confirmation of a sender-constrained token (RFC 7800 section 3.1),
+// the token is bound to the DPoP key thumbprint or the client certificate thumbprint
+type Confirmation struct {
+	JKT     string `json:"jkt,omitempty" bson:"JKT"`
+	X5TS256 string `json:"x5t#S256,omitempty" bson:"X5TS256"`
+}
+
+// Actor the acting party of a delegated token,
+// the nested actor is the prior actor of the delegation chain (RFC 8693 section 4.1)
+type Actor struct {
+	Subject  string `json:"sub" bson:"Subject"`
+	ClientID string `json:"client_id,omitempty" bson:"ClientID"`
+	Actor    *Actor `json:"act,omitempty" bson:"Actor"`
+}
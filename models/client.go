@@ -1,12 +1,33 @@
 package models
 
+import (
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+)
+
 // Client client model
 type Client struct {
-	ID     string
-	Secret string
-	Domain string
-	Public bool
-	UserID string
+	ID                      string
+	Secret                  string
+	Domain                  string
+	Public                  bool
+	UserID                  string
+	RedirectURIs            []string
+	GrantTypes              []oauth2.GrantType
+	ResponseTypes           []oauth2.ResponseType
+	Scope                   string
+	Resources               []string
+	TokenEndpointAuthMethod oauth2.ClientAuthMethod
+	AccessTokenExp          time.Duration
+	RefreshTokenExp         time.Duration
+	RequirePKCE             bool
+	RequirePushedRequest    bool
+	Disabled                bool
+	JWKS                    *JWKSet
+	TLSSubjectDN            string
+	Metadata                *ClientMetadata
+	RegistrationToken       string // the hash of the registration access token
 }
 
 // GetID client id
@@ -33,3 +54,68 @@ func (c *Client) IsPublic() bool {
 func (c *Client) GetUserID() string {
 	return c.UserID
 }
+
+// GetRedirectURIs the registered redirect uris
+func (c *Client) GetRedirectURIs() []string {
+	return c.RedirectURIs
+}
+
+// GetGrantTypes the allowed grant types
+func (c *Client) GetGrantTypes() []oauth2.GrantType {
+	return c.GrantTypes
+}
+
+// GetResponseTypes the allowed response types
+func (c *Client) GetResponseTypes() []oauth2.ResponseType {
+	return c.ResponseTypes
+}
+
+// GetScope the allowed scope
+func (c *Client) GetScope() string {
+	return c.Scope
+}
+
+// GetResources the allowed resources
+func (c *Client) GetResources() []string {
+	return c.Resources
+}
+
+// GetTokenEndpointAuthMethod the token endpoint authentication method
+func (c *Client) GetTokenEndpointAuthMethod() oauth2.ClientAuthMethod {
+	return c.TokenEndpointAuthMethod
+}
+
+// GetAccessTokenExp the access token lifetime of the client
+func (c *Client) GetAccessTokenExp() time.Duration {
+	return c.AccessTokenExp
+}
+
+// GetRefreshTokenExp the refresh token lifetime of the client
+func (c *Client) GetRefreshTokenExp() time.Duration {
+	return c.RefreshTokenExp
+}
+
+// IsPKCERequired the client must use PKCE
+func (c *Client) IsPKCERequired() bool {
+	return c.RequirePKCE
+}
+
+// IsDisabled the client is disabled
+func (c *Client) IsDisabled() bool {
+	return c.Disabled
+}
+
+// IsPushedRequestRequired the client must push the authorization requests
+func (c *Client) IsPushedRequestRequired() bool {
+	return c.RequirePushedRequest
+}
+
+// GetJWKSet the public keys of the client
+func (c *Client) GetJWKSet() *JWKSet {
+	return c.JWKS
+}
+
+// GetTLSClientAuthSubjectDN the subject distinguished name of the client certificate
+func (c *Client) GetTLSClientAuthSubjectDN() string {
+	return c.TLSSubjectDN
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+// ClientMetadata the metadata of the dynamically registered client
+// https://tools.ietf.org/html/rfc7591#section-2
+type ClientMetadata struct {
+	RedirectURIs                       []string `json:"redirect_uris,omitempty"`
+	TokenEndpointAuthMethod            string   `json:"token_endpoint_auth_method,omitempty"`
+	GrantTypes                         []string `json:"grant_types,omitempty"`
+	ResponseTypes                      []string `json:"response_types,omitempty"`
+	ClientName                         string   `json:"client_name,omitempty"`
+	ClientURI                          string   `json:"client_uri,omitempty"`
+	LogoURI                            string   `json:"logo_uri,omitempty"`
+	Scope                              string   `json:"scope,omitempty"`
+	Contacts                           []string `json:"contacts,omitempty"`
+	TOSURI                             string   `json:"tos_uri,omitempty"`
+	PolicyURI                          string   `json:"policy_uri,omitempty"`
+	JWKSURI                            string   `json:"jwks_uri,omitempty"`
+	JWKS                               *JWKSet  `json:"jwks,omitempty"`
+	SoftwareID                         string   `json:"software_id,omitempty"`
+	SoftwareVersion                    string   `json:"software_version,omitempty"`
+	TLSClientAuthSubjectDN             string   `json:"tls_client_auth_subject_dn,omitempty"`
+	RequirePushedAuthorizationRequests bool     `json:"require_pushed_authorization_requests,omitempty"`
+}
+
+// RegisteredClient the client information of the dynamically registered client,
+// its configuration is managed with the registration access token
+type RegisteredClient interface {
+	GetMetadata() *ClientMetadata
+	VerifyRegistrationToken(token string) bool
+}
+
+// HashRegistrationToken the hash of the registration access token,
+// only the hash is stored with the client
+func HashRegistrationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetMetadata the registered metadata of the client
+func (c *Client) GetMetadata() *ClientMetadata {
+	return c.Metadata
+}
+
+// VerifyRegistrationToken check the registration access token of the client
+func (c *Client) VerifyRegistrationToken(token string) bool {
+	if c.RegistrationToken == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(HashRegistrationToken(token)), []byte(c.RegistrationToken)) == 1
+}
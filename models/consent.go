@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+)
+
+// NewConsent create to consent model instance
+func NewConsent() *Consent {
+	return &Consent{}
+}
+
+// Consent consent model
+type Consent struct {
+	UserID    string        `bson:"UserID"`
+	ClientID  string        `bson:"ClientID"`
+	Scope     string        `bson:"Scope"`
+	CreateAt  time.Time     `bson:"CreateAt"`
+	ExpiresIn time.Duration `bson:"ExpiresIn"`
+}
+
+// New create to consent model instance
+func (c *Consent) New() oauth2.ConsentInfo {
+	return NewConsent()
+}
+
+// GetUserID the user id
+func (c *Consent) GetUserID() string {
+	return c.UserID
+}
+
+// SetUserID the user id
+func (c *Consent) SetUserID(userID string) {
+	c.UserID = userID
+}
+
+// GetClientID the client id
+func (c *Consent) GetClientID() string {
+	return c.ClientID
+}
+
+// SetClientID the client id
+func (c *Consent) SetClientID(clientID string) {
+	c.ClientID = clientID
+}
+
+// GetScope the scope the user granted to the client
+func (c *Consent) GetScope() string {
+	return c.Scope
+}
+
+// SetScope the scope the user granted to the client
+func (c *Consent) SetScope(scope string) {
+	c.Scope = scope
+}
+
+// GetCreateAt the time the consent was granted
+func (c *Consent) GetCreateAt() time.Time {
+	return c.CreateAt
+}
+
+// SetCreateAt the time the consent was granted
+func (c *Consent) SetCreateAt(createAt time.Time) {
+	c.CreateAt = createAt
+}
+
+// GetExpiresIn the lifetime of the consent, 0 means it doesn't expire
+func (c *Consent) GetExpiresIn() time.Duration {
+	return c.ExpiresIn
+}
+
+// SetExpiresIn the lifetime of the consent, 0 means it doesn't expire
+func (c *Consent) SetExpiresIn(exp time.Duration) {
+	c.ExpiresIn = exp
+}
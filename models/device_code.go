@@ -0,0 +1,131 @@
+package models
+
+import (
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+)
+
+// NewDeviceCode create to device authorization model instance
+func NewDeviceCode() *DeviceCode {
+	return &DeviceCode{}
+}
+
+// DeviceCode device authorization model
+type DeviceCode struct {
+	ClientID   string                  `bson:"ClientID"`
+	UserID     string                  `bson:"UserID"`
+	Scope      string                  `bson:"Scope"`
+	DeviceCode string                  `bson:"DeviceCode"`
+	UserCode   string                  `bson:"UserCode"`
+	CreateAt   time.Time               `bson:"CreateAt"`
+	ExpiresIn  time.Duration           `bson:"ExpiresIn"`
+	Interval   time.Duration           `bson:"Interval"`
+	LastPollAt time.Time               `bson:"LastPollAt"`
+	Status     oauth2.DeviceCodeStatus `bson:"Status"`
+}
+
+// New create to device authorization model instance
+func (d *DeviceCode) New() oauth2.DeviceCodeInfo {
+	return NewDeviceCode()
+}
+
+// GetClientID the client id
+func (d *DeviceCode) GetClientID() string {
+	return d.ClientID
+}
+
+// SetClientID the client id
+func (d *DeviceCode) SetClientID(clientID string) {
+	d.ClientID = clientID
+}
+
+// GetUserID the user id
+func (d *DeviceCode) GetUserID() string {
+	return d.UserID
+}
+
+// SetUserID the user id
+func (d *DeviceCode) SetUserID(userID string) {
+	d.UserID = userID
+}
+
+// GetScope get scope of authorization
+func (d *DeviceCode) GetScope() string {
+	return d.Scope
+}
+
+// SetScope get scope of authorization
+func (d *DeviceCode) SetScope(scope string) {
+	d.Scope = scope
+}
+
+// GetDeviceCode the device code
+func (d *DeviceCode) GetDeviceCode() string {
+	return d.DeviceCode
+}
+
+// SetDeviceCode the device code
+func (d *DeviceCode) SetDeviceCode(code string) {
+	d.DeviceCode = code
+}
+
+// GetUserCode the user code
+func (d *DeviceCode) GetUserCode() string {
+	return d.UserCode
+}
+
+// SetUserCode the user code
+func (d *DeviceCode) SetUserCode(code string) {
+	d.UserCode = code
+}
+
+// GetCreateAt create Time
+func (d *DeviceCode) GetCreateAt() time.Time {
+	return d.CreateAt
+}
+
+// SetCreateAt create Time
+func (d *DeviceCode) SetCreateAt(createAt time.Time) {
+	d.CreateAt = createAt
+}
+
+// GetExpiresIn the lifetime in seconds of the device code
+func (d *DeviceCode) GetExpiresIn() time.Duration {
+	return d.ExpiresIn
+}
+
+// SetExpiresIn the lifetime in seconds of the device code
+func (d *DeviceCode) SetExpiresIn(exp time.Duration) {
+	d.ExpiresIn = exp
+}
+
+// GetInterval the minimum amount of time that the client should wait between polling requests
+func (d *DeviceCode) GetInterval() time.Duration {
+	return d.Interval
+}
+
+// SetInterval the minimum amount of time that the client should wait between polling requests
+func (d *DeviceCode) SetInterval(interval time.Duration) {
+	d.Interval = interval
+}
+
+// GetLastPollAt the time of the last polling request
+func (d *DeviceCode) GetLastPollAt() time.Time {
+	return d.LastPollAt
+}
+
+// SetLastPollAt the time of the last polling request
+func (d *DeviceCode) SetLastPollAt(pollAt time.Time) {
+	d.LastPollAt = pollAt
+}
+
+// GetStatus the status of the device authorization
+func (d *DeviceCode) GetStatus() oauth2.DeviceCodeStatus {
+	return d.Status
+}
+
+// SetStatus the status of the device authorization
+func (d *DeviceCode) SetStatus(status oauth2.DeviceCodeStatus) {
+	d.Status = status
+}
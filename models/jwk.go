@@ -0,0 +1,170 @@
+package models
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// JWK json web key model of a public key
+// https://tools.ietf.org/html/rfc7517
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// KeySetClient the client information with the public keys of the client,
+// the keys verify the request objects and assertions signed by the client
+type KeySetClient interface {
+	GetJWKSet() *JWKSet
+}
+
+// JWKSet json web key set model
+type JWKSet struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// NewJWK create a signature verification key from the RSA, EC or Ed25519 public key
+func NewJWK(kid, alg string, key crypto.PublicKey) (*JWK, error) {
+	jwk := &JWK{
+		Use: "sig",
+		Alg: alg,
+		Kid: kid,
+	}
+
+	switch v := key.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(v.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(v.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (v.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = v.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(v.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(v.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(v)
+	default:
+		return nil, errors.New("unsupported public key type")
+	}
+	return jwk, nil
+}
+
+// PublicKey the public key represented by the json web key
+func (k *JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.New("unsupported curve")
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if _, err := key.ECDH(); err != nil {
+			return nil, err
+		}
+		return key, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, errors.New("unsupported curve")
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid key size")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, errors.New("unsupported key type")
+}
+
+// Key get the key of the key id, nil if the set does not contain it
+func (s *JWKSet) Key(kid string) *JWK {
+	for _, k := range s.Keys {
+		if k.Kid == kid {
+			return k
+		}
+	}
+	return nil
+}
+
+// Thumbprint the base64url encoded SHA-256 json web key thumbprint
+// https://tools.ietf.org/html/rfc7638
+func (k *JWK) Thumbprint() (string, error) {
+	var members []string
+	switch k.Kty {
+	case "RSA":
+		members = []string{`"e":`, k.E, `"kty":`, k.Kty, `"n":`, k.N}
+	case "EC":
+		members = []string{`"crv":`, k.Crv, `"kty":`, k.Kty, `"x":`, k.X, `"y":`, k.Y}
+	case "OKP":
+		members = []string{`"crv":`, k.Crv, `"kty":`, k.Kty, `"x":`, k.X}
+	default:
+		return "", errors.New("unsupported key type")
+	}
+
+	var buf strings.Builder
+	buf.WriteString("{")
+	for i := 0; i < len(members); i += 2 {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString(members[i])
+		buf.WriteString(strconv.Quote(members[i+1]))
+	}
+	buf.WriteString("}")
+
+	sum := sha256.Sum256([]byte(buf.String()))
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,44 @@
+package models_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/go-oauth2/oauth2/v4/models"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestJWK(t *testing.T) {
+	Convey("Test json web key thumbprint", t, func() {
+		// https://tools.ietf.org/html/rfc7638#section-3.1
+		jwk := &models.JWK{
+			Kty: "RSA",
+			N:   "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+			E:   "AQAB",
+			Alg: "RS256",
+			Kid: "2011-04-29",
+		}
+		v, err := jwk.Thumbprint()
+		So(err, ShouldBeNil)
+		So(v, ShouldEqual, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs")
+	})
+
+	Convey("Test json web key public key", t, func() {
+		key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		jwk, err := models.NewJWK("kid", "ES384", &key.PublicKey)
+		So(err, ShouldBeNil)
+		So(jwk.Kty, ShouldEqual, "EC")
+		So(jwk.Crv, ShouldEqual, "P-384")
+
+		pub, err := jwk.PublicKey()
+		So(err, ShouldBeNil)
+		So(key.PublicKey.Equal(pub), ShouldBeTrue)
+
+		set := &models.JWKSet{Keys: []*models.JWK{jwk}}
+		So(set.Key("kid"), ShouldEqual, jwk)
+		So(set.Key("other"), ShouldBeNil)
+	})
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+)
+
+// NewPushedRequest create to pushed authorization request model instance
+func NewPushedRequest() *PushedRequest {
+	return &PushedRequest{Params: make(url.Values)}
+}
+
+// PushedRequest pushed authorization request model
+type PushedRequest struct {
+	RequestURI string        `bson:"RequestURI"`
+	ClientID   string        `bson:"ClientID"`
+	Params     url.Values    `bson:"Params"`
+	CreateAt   time.Time     `bson:"CreateAt"`
+	ExpiresIn  time.Duration `bson:"ExpiresIn"`
+}
+
+// New create to pushed authorization request model instance
+func (p *PushedRequest) New() oauth2.PushedRequestInfo {
+	return NewPushedRequest()
+}
+
+// GetRequestURI the request uri referencing the authorization request
+func (p *PushedRequest) GetRequestURI() string {
+	return p.RequestURI
+}
+
+// SetRequestURI the request uri referencing the authorization request
+func (p *PushedRequest) SetRequestURI(requestURI string) {
+	p.RequestURI = requestURI
+}
+
+// GetClientID the client id
+func (p *PushedRequest) GetClientID() string {
+	return p.ClientID
+}
+
+// SetClientID the client id
+func (p *PushedRequest) SetClientID(clientID string) {
+	p.ClientID = clientID
+}
+
+// GetParams the parameters of the authorization request
+func (p *PushedRequest) GetParams() url.Values {
+	return p.Params
+}
+
+// SetParams the parameters of the authorization request
+func (p *PushedRequest) SetParams(params url.Values) {
+	p.Params = params
+}
+
+// GetCreateAt create Time
+func (p *PushedRequest) GetCreateAt() time.Time {
+	return p.CreateAt
+}
+
+// SetCreateAt create Time
+func (p *PushedRequest) SetCreateAt(createAt time.Time) {
+	p.CreateAt = createAt
+}
+
+// GetExpiresIn the lifetime in seconds of the request uri
+func (p *PushedRequest) GetExpiresIn() time.Duration {
+	return p.ExpiresIn
+}
+
+// SetExpiresIn the lifetime in seconds of the request uri
+func (p *PushedRequest) SetExpiresIn(exp time.Duration) {
+	p.ExpiresIn = exp
+}
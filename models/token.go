@@ -14,22 +14,30 @@ func NewToken() *Token {
 
 // Token token model
 type Token struct {
-	ClientID            string        `bson:"ClientID"`
-	UserID              string        `bson:"UserID"`
-	RedirectURI         string        `bson:"RedirectURI"`
-	Scope               string        `bson:"Scope"`
-	Code                string        `bson:"Code"`
-	CodeChallenge       string        `bson:"CodeChallenge"`
-	CodeChallengeMethod string        `bson:"CodeChallengeMethod"`
-	CodeCreateAt        time.Time     `bson:"CodeCreateAt"`
-	CodeExpiresIn       time.Duration `bson:"CodeExpiresIn"`
-	Access              string        `bson:"Access"`
-	AccessCreateAt      time.Time     `bson:"AccessCreateAt"`
-	AccessExpiresIn     time.Duration `bson:"AccessExpiresIn"`
-	Refresh             string        `bson:"Refresh"`
-	RefreshCreateAt     time.Time     `bson:"RefreshCreateAt"`
-	RefreshExpiresIn    time.Duration `bson:"RefreshExpiresIn"`
-	Extension           url.Values    `bson:"Extension"`
+	ClientID             string                      `bson:"ClientID"`
+	UserID               string                      `bson:"UserID"`
+	RedirectURI          string                      `bson:"RedirectURI"`
+	Scope                string                      `bson:"Scope"`
+	Code                 string                      `bson:"Code"`
+	CodeChallenge        string                      `bson:"CodeChallenge"`
+	CodeChallengeMethod  string                      `bson:"CodeChallengeMethod"`
+	CodeCreateAt         time.Time                   `bson:"CodeCreateAt"`
+	CodeExpiresIn        time.Duration               `bson:"CodeExpiresIn"`
+	Access               string                      `bson:"Access"`
+	AccessCreateAt       time.Time                   `bson:"AccessCreateAt"`
+	AccessExpiresIn      time.Duration               `bson:"AccessExpiresIn"`
+	Refresh              string                      `bson:"Refresh"`
+	RefreshCreateAt      time.Time                   `bson:"RefreshCreateAt"`
+	RefreshExpiresIn     time.Duration               `bson:"RefreshExpiresIn"`
+	Nonce                string                      `bson:"Nonce"`
+	AuthTime             time.Time                   `bson:"AuthTime"`
+	IDToken              string                      `bson:"IDToken"`
+	Audience             []string                    `bson:"Audience"`
+	Actor                *oauth2.Actor               `bson:"Actor"`
+	Confirmation         *oauth2.Confirmation        `bson:"Confirmation"`
+	Family               string                      `bson:"Family"`
+	AuthorizationDetails oauth2.AuthorizationDetails `bson:"AuthorizationDetails"`
+	Extension            url.Values                  `bson:"Extension"`
 }
 
 // New create to token model instance
@@ -187,6 +195,36 @@ func (t *Token) SetRefreshExpiresIn(exp time.Duration) {
 	t.RefreshExpiresIn = exp
 }
 
+// GetNonce the nonce of the OpenID Connect authentication request
+func (t *Token) GetNonce() string {
+	return t.Nonce
+}
+
+// SetNonce the nonce of the OpenID Connect authentication request
+func (t *Token) SetNonce(nonce string) {
+	t.Nonce = nonce
+}
+
+// GetAuthTime the time when the end-user authentication occurred
+func (t *Token) GetAuthTime() time.Time {
+	return t.AuthTime
+}
+
+// SetAuthTime the time when the end-user authentication occurred
+func (t *Token) SetAuthTime(authTime time.Time) {
+	t.AuthTime = authTime
+}
+
+// GetIDToken OpenID Connect id token
+func (t *Token) GetIDToken() string {
+	return t.IDToken
+}
+
+// SetIDToken OpenID Connect id token
+func (t *Token) SetIDToken(idToken string) {
+	t.IDToken = idToken
+}
+
 // GetExtension extension of token
 func (t *Token) GetExtension() url.Values {
 	return t.Extension
@@ -196,3 +234,53 @@ func (t *Token) GetExtension() url.Values {
 func (t *Token) SetExtension(e url.Values) {
 	t.Extension = e
 }
+
+// GetAudience the intended audience of the token
+func (t *Token) GetAudience() []string {
+	return t.Audience
+}
+
+// SetAudience the intended audience of the token
+func (t *Token) SetAudience(audience []string) {
+	t.Audience = audience
+}
+
+// GetActor the acting party of the delegated token
+func (t *Token) GetActor() *oauth2.Actor {
+	return t.Actor
+}
+
+// SetActor the acting party of the delegated token
+func (t *Token) SetActor(actor *oauth2.Actor) {
+	t.Actor = actor
+}
+
+// GetConfirmation the key confirmation of the sender-constrained token
+func (t *Token) GetConfirmation() *oauth2.Confirmation {
+	return t.Confirmation
+}
+
+// SetConfirmation the key confirmation of the sender-constrained token
+func (t *Token) SetConfirmation(cnf *oauth2.Confirmation) {
+	t.Confirmation = cnf
+}
+
+// GetFamily the family of the tokens issued from the same grant
+func (t *Token) GetFamily() string {
+	return t.Family
+}
+
+// SetFamily the family of the tokens issued from the same grant
+func (t *Token) SetFamily(family string) {
+	t.Family = family
+}
+
+// GetAuthorizationDetails the authorization details the token is granted for
+func (t *Token) GetAuthorizationDetails() oauth2.AuthorizationDetails {
+	return t.AuthorizationDetails
+}
+
+// SetAuthorizationDetails the authorization details the token is granted for
+func (t *Token) SetAuthorizationDetails(details oauth2.AuthorizationDetails) {
+	t.AuthorizationDetails = details
+}
@@ -0,0 +1,171 @@
+package oauth2
+
+import (
+	"strings"
+
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// the delimiter of the segments of the hierarchical scope token,
+// and the segment of the granted scope token matching any segment
+const (
+	ScopeDelimiter = ":"
+	ScopeWildcard  = "*"
+)
+
+// ScopeMatcher check whether the granted scope token implies the required scope token,
+// the nil matcher only matches the same scope token
+type ScopeMatcher func(granted, required string) bool
+
+// MatchScope check whether the granted scope token implies the required scope token,
+// the scope token implies its descendants (repo implies repo:read) and the wildcard segment
+// matches any segment of the required scope token (project:*:read implies project:1234:read),
+// the bare wildcard and the absolute URI scope tokens (https://..., urn:...) only match the same scope token
+func MatchScope(granted, required string) bool {
+	if granted == required {
+		return true
+	} else if granted == ScopeWildcard || uriScope(granted) || uriScope(required) {
+		return false
+	}
+
+	gs := strings.Split(granted, ScopeDelimiter)
+	rs := strings.Split(required, ScopeDelimiter)
+	if len(gs) > len(rs) {
+		return false
+	}
+	for i, v := range gs {
+		if v != ScopeWildcard && v != rs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// check whether the scope token is an absolute URI, its colons aren't the delimiters of the segments
+func uriScope(v string) bool {
+	return strings.Contains(v, "://") || strings.HasPrefix(strings.ToLower(v), "urn:")
+}
+
+// HierarchicalScopes create the scope matcher where only the listed scope tokens
+// imply their descendants and wildcard matches, the other scope tokens only match the same scope token
+func HierarchicalScopes(names ...string) ScopeMatcher {
+	hierarchical := NewScope(names...)
+	return func(granted, required string) bool {
+		return granted == required || (hierarchical.Has(granted) && MatchScope(granted, required))
+	}
+}
+
+// Scope the scope tokens of the space-delimited scope parameter (RFC 6749 section 3.3),
+// the order of the tokens is kept and the duplicates are removed
+type Scope []string
+
+// check the scope token only contains the allowed characters,
+// scope-token = 1*( %x21 / %x23-5B / %x5D-7E )
+func validScopeToken(v string) bool {
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if c < 0x21 || c > 0x7e || c == '"' || c == '\\' {
+			return false
+		}
+	}
+	return v != ""
+}
+
+// ParseScope parse and normalize the space-delimited scope parameter,
+// errors.ErrInvalidScope is returned for the malformed scope token
+func ParseScope(s string) (Scope, error) {
+	values := strings.Fields(s)
+	for _, v := range values {
+		if !validScopeToken(v) {
+			return nil, errors.ErrInvalidScope
+		}
+	}
+	return NewScope(values...), nil
+}
+
+// NewScope create the scope of the scope tokens without the duplicates
+func NewScope(values ...string) Scope {
+	scope := make(Scope, 0, len(values))
+	for _, v := range values {
+		if v != "" && !scope.Has(v) {
+			scope = append(scope, v)
+		}
+	}
+	return scope
+}
+
+// String the space-delimited scope parameter
+func (s Scope) String() string {
+	return strings.Join(s, " ")
+}
+
+// Has check whether the scope contains the scope token
+func (s Scope) Has(v string) bool {
+	for _, sv := range s {
+		if sv == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains check whether the scope contains all scope tokens of the other scope
+func (s Scope) Contains(o Scope) bool {
+	for _, v := range o {
+		if !s.Has(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Union the scope tokens of both scopes
+func (s Scope) Union(o Scope) Scope {
+	return NewScope(append(append(Scope{}, s...), o...)...)
+}
+
+// Intersect the scope tokens contained in both scopes
+func (s Scope) Intersect(o Scope) Scope {
+	scope := Scope{}
+	for _, v := range s {
+		if o.Has(v) {
+			scope = append(scope, v)
+		}
+	}
+	return scope
+}
+
+// Difference the scope tokens not contained in the other scope
+func (s Scope) Difference(o Scope) Scope {
+	scope := Scope{}
+	for _, v := range s {
+		if !o.Has(v) {
+			scope = append(scope, v)
+		}
+	}
+	return scope
+}
+
+// Implies check whether a scope token of the scope implies the required scope token,
+// the scope tokens are compared by the matcher, or only match the same scope token when it is nil
+func (s Scope) Implies(required string, match ScopeMatcher) bool {
+	if match == nil {
+		return s.Has(required)
+	}
+	for _, v := range s {
+		if match(v, required) {
+			return true
+		}
+	}
+	return false
+}
+
+// ImpliesAll check whether the scope implies all scope tokens of the required scope
+func (s Scope) ImpliesAll(required Scope, match ScopeMatcher) bool {
+	for _, v := range required {
+		if !s.Implies(v, match) {
+			return false
+		}
+	}
+	return true
+}
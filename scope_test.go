@@ -0,0 +1,78 @@
+package oauth2_test
+
+import (
+	"testing"
+
+	"github.com/go-oauth2/oauth2/v4"
+)
+
+func TestParseScope(t *testing.T) {
+	scope, err := oauth2.ParseScope("  read write\tread profile ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scope.String() != "read write profile" {
+		t.Fatalf("unexpected scope: %s", scope)
+	}
+
+	if _, err := oauth2.ParseScope(`read "write"`); err == nil {
+		t.Fatal("malformed scope accepted")
+	}
+	if _, err := oauth2.ParseScope("read\\write"); err == nil {
+		t.Fatal("malformed scope accepted")
+	}
+}
+
+func TestScopeSet(t *testing.T) {
+	a := oauth2.NewScope("read", "write")
+	b := oauth2.NewScope("write", "profile")
+
+	if v := a.Union(b).String(); v != "read write profile" {
+		t.Fatalf("unexpected union: %s", v)
+	}
+	if v := a.Intersect(b).String(); v != "write" {
+		t.Fatalf("unexpected intersection: %s", v)
+	}
+	if v := a.Difference(b).String(); v != "read" {
+		t.Fatalf("unexpected difference: %s", v)
+	}
+	if !a.Contains(oauth2.NewScope("write")) || a.Contains(b) {
+		t.Fatal("unexpected containment")
+	}
+}
+
+func TestMatchScope(t *testing.T) {
+	cases := []struct {
+		granted, required string
+		match             bool
+	}{
+		{"repo", "repo", true},
+		{"repo", "repo:read", true},
+		{"repo", "repository", false},
+		{"repo:read", "repo", false},
+		{"project:*:read", "project:1234:read", true},
+		{"project:*:read", "project:1234:write", false},
+		{"project:*", "project:1234:write", true},
+		{"project:1234:read", "project:*:read", false},
+		{"*", "admin", false},
+		{"*", "*", true},
+		{"https://api.example.com", "https://api.example.com/read", false},
+		{"urn:example", "urn:example:read", false},
+	}
+	for _, c := range cases {
+		if oauth2.MatchScope(c.granted, c.required) != c.match {
+			t.Errorf("unexpected match of %s and %s", c.granted, c.required)
+		}
+	}
+
+	scope := oauth2.NewScope("repo", "project:*:read", "admin")
+	if scope.Implies("repo:write", nil) || !scope.Implies("repo", nil) {
+		t.Fatal("unexpected implication without the matcher")
+	}
+
+	match := oauth2.HierarchicalScopes("repo", "project:*:read")
+	if !scope.ImpliesAll(oauth2.NewScope("repo:write", "project:1:read"), match) ||
+		scope.Implies("admin:write", match) || scope.Implies("user", match) {
+		t.Fatal("unexpected implication")
+	}
+}
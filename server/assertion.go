@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// get the verification keys of the key set for the signed jwt,
+// the key id selects the key when the jwt names one
+func verificationKeys(keys *models.JWKSet, t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	var set jwt.VerificationKeySet
+	for _, k := range keys.Keys {
+		if (kid != "" && k.Kid != kid) || k.Use == "enc" ||
+			(k.Alg != "" && k.Alg != t.Method.Alg()) {
+			continue
+		}
+		key, err := k.PublicKey()
+		if err != nil {
+			continue
+		}
+		set.Keys = append(set.Keys, key)
+	}
+
+	if len(set.Keys) == 0 {
+		return nil, errors.ErrInvalidGrant
+	}
+	return set, nil
+}
+
+// check the audience of the jwt assertion identifies the authorization server
+func (s *Server) validAssertionAudience(aud jwt.ClaimStrings) bool {
+	for _, v := range aud {
+		if v == "" {
+			continue
+		}
+		if v == s.Config.Issuer || v == s.Config.TokenEndpoint {
+			return true
+		}
+	}
+	return false
+}
+
+// verify the jwt assertion signed by the trusted issuer,
+// the jwt id of the assertion is used only once when the jwt id store is set
+// https://tools.ietf.org/html/rfc7523#section-3
+func (s *Server) verifyAssertion(ctx context.Context, assertion string, keysFn IssuerKeysHandler) (*jwt.RegisteredClaims, error) {
+	if keysFn == nil {
+		return nil, errors.ErrInvalidGrant
+	}
+
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(assertion, claims, func(t *jwt.Token) (interface{}, error) {
+		if claims.Issuer == "" {
+			return nil, errors.ErrInvalidGrant
+		}
+		keys, err := keysFn(ctx, claims.Issuer)
+		if err != nil {
+			return nil, err
+		} else if keys == nil {
+			return nil, errors.ErrInvalidGrant
+		}
+		return verificationKeys(keys, t)
+	}, jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, errors.ErrInvalidGrant
+	}
+
+	if claims.Subject == "" || !s.validAssertionAudience(claims.Audience) {
+		return nil, errors.ErrInvalidGrant
+	}
+
+	if err := s.useJTI(ctx, claims.Issuer, claims.ID, claims.ExpiresAt.Time, errors.ErrInvalidGrant); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// NewAuthorizationDetailsRegistry create the registry of the authorization details types known by the server
+func NewAuthorizationDetailsRegistry() *AuthorizationDetailsRegistry {
+	return &AuthorizationDetailsRegistry{handlers: make(map[string]AuthorizationDetailHandler)}
+}
+
+// AuthorizationDetailsRegistry the authorization details types known by the server and their validation handlers,
+// the authorization details of the unknown types are rejected with invalid_authorization_details
+type AuthorizationDetailsRegistry struct {
+	mu       sync.RWMutex
+	types    []string
+	handlers map[string]AuthorizationDetailHandler
+}
+
+// Register register the known authorization details type, the handler validates the objects of the type
+// and may be nil when the objects of the type are accepted as they are
+func (ar *AuthorizationDetailsRegistry) Register(typ string, handler AuthorizationDetailHandler) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if _, ok := ar.handlers[typ]; !ok {
+		ar.types = append(ar.types, typ)
+	}
+	ar.handlers[typ] = handler
+}
+
+// Types the known authorization details types in the order they were registered
+func (ar *AuthorizationDetailsRegistry) Types() []string {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	return append([]string{}, ar.types...)
+}
+
+// Validate check the types of the authorization details are known,
+// and validate each object with the handler of its type
+func (ar *AuthorizationDetailsRegistry) Validate(ctx context.Context, clientID string, details oauth2.AuthorizationDetails) error {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	for _, detail := range details {
+		handler, ok := ar.handlers[detail.GetType()]
+		if !ok {
+			return errors.ErrInvalidAuthorizationDetails
+		} else if handler == nil {
+			continue
+		}
+		if err := handler(ctx, clientID, detail); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parse and validate the requested authorization details of the client,
+// the authorization details are rejected when the authorization details registry isn't set
+// https://tools.ietf.org/html/rfc9396#section-5
+func (s *Server) parseAuthorizationDetails(ctx context.Context, clientID, v string) (oauth2.AuthorizationDetails, error) {
+	details, err := oauth2.ParseAuthorizationDetails(v)
+	if err != nil || len(details) == 0 {
+		return nil, err
+	}
+
+	ar := s.AuthorizationDetailsRegistry
+	if ar == nil {
+		return nil, errors.ErrInvalidAuthorizationDetails
+	}
+	if err := ar.Validate(ctx, clientID, details); err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+// get the authorization details of the token
+func tokenAuthorizationDetails(ti oauth2.TokenInfo) oauth2.AuthorizationDetails {
+	if dti, ok := ti.(oauth2.AuthorizationDetailsTokenInfo); ok {
+		return dti.GetAuthorizationDetails()
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+)
+
+func TestAuthorizationDetails(t *testing.T) {
+	tsrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testServer(t, w, r)
+	}))
+	defer tsrv.Close()
+	e := httpexpect.New(t, tsrv.URL)
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	mgr.MapClientStorage(clientStore("", false))
+
+	srv = server.NewDefaultServer(mgr)
+	srv.SetPasswordAuthorizationHandler(func(ctx context.Context, clientID, username, password string) (string, error) {
+		return "000000", nil
+	})
+
+	transfer := `[{"type":"payment_initiation","instructedAmount":{"currency":"EUR","amount":"100.00"},"creditorAccount":{"iban":"DE02100100109307118603"}}]`
+
+	// the authorization details are rejected without the registry
+	e.POST("/token").
+		WithFormField("grant_type", "client_credentials").
+		WithFormField("authorization_details", transfer).
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Equal("invalid_authorization_details")
+
+	registry := server.NewAuthorizationDetailsRegistry()
+	registry.Register("payment_initiation", func(ctx context.Context, clientID string, detail oauth2.AuthorizationDetail) error {
+		var payment struct {
+			InstructedAmount struct {
+				Currency string `json:"currency"`
+				Amount   string `json:"amount"`
+			} `json:"instructedAmount"`
+		}
+		if err := detail.Decode(&payment); err != nil || payment.InstructedAmount.Currency != "EUR" {
+			return errors.ErrInvalidAuthorizationDetails
+		}
+		return nil
+	})
+	srv.SetAuthorizationDetailsRegistry(registry)
+
+	if v := srv.GetMetadata()["authorization_details_types_supported"]; len(v.([]string)) != 1 {
+		t.Fatalf("unexpected authorization_details_types_supported: %v", v)
+	}
+
+	for _, details := range []string{
+		`[{"type":"account_information"}]`,
+		`[{"type":"payment_initiation","instructedAmount":{"currency":"USD","amount":"100.00"}}]`,
+		`{"type":"payment_initiation"}`,
+	} {
+		e.POST("/token").
+			WithFormField("grant_type", "client_credentials").
+			WithFormField("authorization_details", details).
+			WithBasicAuth(clientID, clientSecret).
+			Expect().
+			Status(http.StatusBadRequest).
+			JSON().Object().Value("error").Equal("invalid_authorization_details")
+	}
+
+	resObj := e.POST("/token").
+		WithFormField("grant_type", "password").
+		WithFormField("username", "admin").
+		WithFormField("password", "123456").
+		WithFormField("authorization_details", transfer).
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	resObj.Value("authorization_details").Array().Element(0).Object().
+		Value("creditorAccount").Object().Value("iban").Equal("DE02100100109307118603")
+
+	e.POST("/introspect").
+		WithFormField("token", resObj.Value("access_token").String().Raw()).
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("authorization_details").Array().Length().Equal(1)
+
+	// the refreshed token can't be granted for other authorization details
+	e.POST("/token").
+		WithFormField("grant_type", "refresh_token").
+		WithFormField("refresh_token", resObj.Value("refresh_token").String().Raw()).
+		WithFormField("authorization_details", `[{"type":"payment_initiation","instructedAmount":{"currency":"EUR","amount":"10000.00"}}]`).
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Equal("invalid_authorization_details")
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// check the authorization requests of the client must use PKCE
+func (s *Server) pkceRequired(ctx context.Context, clientID string) (bool, error) {
+	if s.Config.ForcePKCE {
+		return true, nil
+	}
+
+	cli, err := s.Manager.GetClient(ctx, clientID)
+	if err != nil {
+		return false, err
+	}
+	if pcli, ok := cli.(oauth2.PKCEClientInfo); ok {
+		return pcli.IsPKCERequired(), nil
+	}
+	return false, nil
+}
+
+// get the redirect uri of the authorization request without one,
+// the client must have registered only a single redirect uri
+func (s *Server) defaultRedirectURI(ctx context.Context, clientID string) (string, error) {
+	cli, err := s.Manager.GetClient(ctx, clientID)
+	if err != nil {
+		return "", err
+	}
+
+	if rcli, ok := cli.(oauth2.RedirectURIsClientInfo); ok {
+		switch uris := rcli.GetRedirectURIs(); len(uris) {
+		case 0:
+		case 1:
+			return uris[0], nil
+		default:
+			return "", errors.ErrInvalidRequest
+		}
+	}
+	return cli.GetDomain(), nil
+}
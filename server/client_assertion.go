@@ -0,0 +1,97 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// the signing algorithms of the client assertions
+var (
+	privateKeyJWTSigningMethods = []string{
+		"RS256", "RS384", "RS512", "PS256", "PS384", "PS512",
+		"ES256", "ES384", "ES512", "EdDSA",
+	}
+	clientSecretJWTSigningMethods = []string{"HS256", "HS384", "HS512"}
+)
+
+// verify the jwt client assertion of the request (RFC 7523 section 3),
+// the issuer and the subject are the client, the jwt id of the assertion
+// is used only once when the jwt id store is set
+func (s *Server) verifyClientAssertion(r *http.Request, method oauth2.ClientAuthMethod) (string, error) {
+	assertion := r.FormValue("client_assertion")
+	if r.FormValue("client_assertion_type") != oauth2.ClientAssertionType || assertion == "" {
+		return "", errors.ErrInvalidClient
+	}
+	ctx := r.Context()
+
+	methods := privateKeyJWTSigningMethods
+	if method == oauth2.ClientSecretJWT {
+		methods = clientSecretJWTSigningMethods
+	}
+
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(assertion, claims, func(t *jwt.Token) (interface{}, error) {
+		if claims.Issuer == "" {
+			return nil, errors.ErrInvalidClient
+		}
+
+		if method == oauth2.ClientSecretJWT {
+			cli, err := s.Manager.GetClient(ctx, claims.Issuer)
+			if err != nil {
+				return nil, err
+			} else if _, ok := cli.(oauth2.ClientPasswordVerifier); ok || cli.GetSecret() == "" {
+				// the hashed secret can't verify the signature
+				return nil, errors.ErrInvalidClient
+			}
+			return []byte(cli.GetSecret()), nil
+		}
+
+		keys, err := s.clientKeys(ctx, claims.Issuer)
+		if err != nil {
+			return nil, err
+		} else if keys == nil {
+			return nil, errors.ErrInvalidClient
+		}
+		return verificationKeys(keys, t)
+	}, jwt.WithValidMethods(methods), jwt.WithExpirationRequired())
+	if err != nil {
+		return "", errors.ErrInvalidClient
+	}
+
+	clientID := claims.Issuer
+	if claims.Subject != clientID || !s.validAssertionAudience(claims.Audience) {
+		return "", errors.ErrInvalidClient
+	} else if v := r.FormValue("client_id"); v != "" && v != clientID {
+		return "", errors.ErrInvalidClient
+	}
+
+	if err := s.useJTI(ctx, "client "+clientID, claims.ID, claims.ExpiresAt.Time, errors.ErrInvalidClient); err != nil {
+		return "", err
+	}
+
+	setClientAuthMethod(r, method)
+	return clientID, nil
+}
+
+// ClientPrivateKeyJWTHandler get client data by the jwt client assertion signed
+// with the private key of the client, its public keys are registered
+func (s *Server) ClientPrivateKeyJWTHandler(r *http.Request) (string, string, error) {
+	clientID, err := s.verifyClientAssertion(r, oauth2.PrivateKeyJWT)
+	if err != nil {
+		return "", "", err
+	}
+	return clientID, "", nil
+}
+
+// ClientSecretJWTHandler get client data by the jwt client assertion signed
+// with the HMAC of the client secret
+func (s *Server) ClientSecretJWTHandler(r *http.Request) (string, string, error) {
+	clientID, err := s.verifyClientAssertion(r, oauth2.ClientSecretJWT)
+	if err != nil {
+		return "", "", err
+	}
+	return clientID, "", nil
+}
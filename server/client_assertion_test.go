@@ -0,0 +1,199 @@
+package server_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const testTokenEndpoint = "https://as.example.com/token"
+
+// sign the client assertion of the claims
+func clientAssertion(t *testing.T, method jwt.SigningMethod, key interface{}, claims jwt.RegisteredClaims) string {
+	if claims.ExpiresAt == nil {
+		claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Minute))
+	}
+	if claims.ID == "" {
+		claims.ID = uuid.Must(uuid.NewRandom()).String()
+	}
+	v, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func assertionServer(t *testing.T, cli *models.Client) *httpexpect.Expect {
+	tsrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testServer(t, w, r)
+	}))
+	t.Cleanup(tsrv.Close)
+
+	clientStore := store.NewClientStore()
+	clientStore.Set(cli.ID, cli)
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	mgr.MapClientStorage(clientStore)
+
+	cfg := server.NewConfig()
+	cfg.TokenEndpoint = testTokenEndpoint
+	srv = server.NewServer(cfg, mgr)
+	jstore, err := store.NewMemoryJTIStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.SetJTIStore(jstore)
+
+	return httpexpect.New(t, tsrv.URL)
+}
+
+func TestClientPrivateKeyJWTHandler(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwk, err := models.NewJWK("client-key", "ES256", key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := assertionServer(t, &models.Client{
+		ID:     clientID,
+		Secret: clientSecret,
+		JWKS:   &models.JWKSet{Keys: []*models.JWK{jwk}},
+	})
+	srv.SetClientInfoHandler(srv.ClientPrivateKeyJWTHandler)
+
+	request := func(assertion string) *httpexpect.Response {
+		return e.POST("/token").
+			WithFormField("grant_type", "client_credentials").
+			WithFormField("client_assertion_type", oauth2.ClientAssertionType).
+			WithFormField("client_assertion", assertion).
+			Expect()
+	}
+
+	valid := clientAssertion(t, jwt.SigningMethodES256, key, jwt.RegisteredClaims{
+		Issuer:   clientID,
+		Subject:  clientID,
+		Audience: jwt.ClaimStrings{testTokenEndpoint},
+	})
+	request(valid).
+		Status(http.StatusOK).
+		JSON().Object().Value("access_token").String().NotEmpty()
+
+	// replayed jwt id
+	request(valid).Status(http.StatusUnauthorized)
+
+	// the replay can't be detected without the jwt id store
+	jstore := srv.JTIStore
+	srv.SetJTIStore(nil)
+	request(clientAssertion(t, jwt.SigningMethodES256, key, jwt.RegisteredClaims{
+		Issuer:   clientID,
+		Subject:  clientID,
+		Audience: jwt.ClaimStrings{testTokenEndpoint},
+	})).Status(http.StatusUnauthorized)
+	srv.SetJTIStore(jstore)
+
+	// the audience isn't the token endpoint
+	request(clientAssertion(t, jwt.SigningMethodES256, key, jwt.RegisteredClaims{
+		Issuer:   clientID,
+		Subject:  clientID,
+		Audience: jwt.ClaimStrings{"https://other.example.com/token"},
+	})).Status(http.StatusUnauthorized)
+
+	// the subject isn't the client
+	request(clientAssertion(t, jwt.SigningMethodES256, key, jwt.RegisteredClaims{
+		Issuer:   clientID,
+		Subject:  "other",
+		Audience: jwt.ClaimStrings{testTokenEndpoint},
+	})).Status(http.StatusUnauthorized)
+
+	// signed with a key that isn't registered
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request(clientAssertion(t, jwt.SigningMethodES256, other, jwt.RegisteredClaims{
+		Issuer:   clientID,
+		Subject:  clientID,
+		Audience: jwt.ClaimStrings{testTokenEndpoint},
+	})).Status(http.StatusUnauthorized)
+
+	// the symmetric algorithm isn't accepted
+	request(clientAssertion(t, jwt.SigningMethodHS256, []byte(clientSecret), jwt.RegisteredClaims{
+		Issuer:   clientID,
+		Subject:  clientID,
+		Audience: jwt.ClaimStrings{testTokenEndpoint},
+	})).Status(http.StatusUnauthorized)
+
+	// the wrong client assertion type
+	e.POST("/token").
+		WithFormField("grant_type", "client_credentials").
+		WithFormField("client_assertion_type", "urn:example:other").
+		WithFormField("client_assertion", clientAssertion(t, jwt.SigningMethodES256, key, jwt.RegisteredClaims{
+			Issuer:   clientID,
+			Subject:  clientID,
+			Audience: jwt.ClaimStrings{testTokenEndpoint},
+		})).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
+
+func TestClientSecretJWTHandler(t *testing.T) {
+	e := assertionServer(t, &models.Client{
+		ID:     clientID,
+		Secret: clientSecret,
+	})
+	srv.SetClientInfoHandler(srv.ClientSecretJWTHandler)
+
+	request := func(assertion string) *httpexpect.Response {
+		return e.POST("/token").
+			WithFormField("grant_type", "client_credentials").
+			WithFormField("client_id", clientID).
+			WithFormField("client_assertion_type", oauth2.ClientAssertionType).
+			WithFormField("client_assertion", assertion).
+			Expect()
+	}
+
+	request(clientAssertion(t, jwt.SigningMethodHS256, []byte(clientSecret), jwt.RegisteredClaims{
+		Issuer:   clientID,
+		Subject:  clientID,
+		Audience: jwt.ClaimStrings{testTokenEndpoint},
+	})).
+		Status(http.StatusOK).
+		JSON().Object().Value("access_token").String().NotEmpty()
+
+	// signed with a different secret
+	request(clientAssertion(t, jwt.SigningMethodHS256, []byte("99999999"), jwt.RegisteredClaims{
+		Issuer:   clientID,
+		Subject:  clientID,
+		Audience: jwt.ClaimStrings{testTokenEndpoint},
+	})).Status(http.StatusUnauthorized)
+
+	// the expiration time is required
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ID:       uuid.Must(uuid.NewRandom()).String(),
+		Issuer:   clientID,
+		Subject:  clientID,
+		Audience: jwt.ClaimStrings{testTokenEndpoint},
+	})
+	v, err := token.SignedString([]byte(clientSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	request(v).Status(http.StatusUnauthorized)
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// the context key of the method the client of the request is authenticated with
+type clientAuthKey struct{}
+
+// record the method the client info handler has authenticated the client of the request with
+func setClientAuthMethod(r *http.Request, method oauth2.ClientAuthMethod) {
+	if p, ok := r.Context().Value(clientAuthKey{}).(*oauth2.ClientAuthMethod); ok {
+		*p = method
+	}
+}
+
+// get the client data of the request with the client info handler,
+// the method is set when the handler has already authenticated the client without the secret
+func (s *Server) clientInfo(r *http.Request) (clientID, clientSecret string, method oauth2.ClientAuthMethod, err error) {
+	r = r.WithContext(context.WithValue(r.Context(), clientAuthKey{}, &method))
+	clientID, clientSecret, err = s.ClientInfoHandler(r)
+	return
+}
+
+// check the client is authenticated with its registered token endpoint authentication method,
+// the method of the client secret is told by where the client secret is presented
+func (s *Server) checkClientAuthMethod(r *http.Request, clientID, clientSecret string, method oauth2.ClientAuthMethod) error {
+	cli, err := s.Manager.GetClient(r.Context(), clientID)
+	if err != nil {
+		return errors.ErrInvalidClient
+	}
+	acli, ok := cli.(oauth2.AuthMethodClientInfo)
+	if !ok || acli.GetTokenEndpointAuthMethod() == "" {
+		return nil
+	}
+
+	if method == "" {
+		if _, _, ok := r.BasicAuth(); ok && clientSecret != "" {
+			method = oauth2.ClientSecretBasic
+		} else if clientSecret != "" {
+			method = oauth2.ClientSecretPost
+		} else {
+			method = oauth2.ClientAuthNone
+		}
+	}
+
+	if method != acli.GetTokenEndpointAuthMethod() {
+		return errors.ErrInvalidClient
+	}
+	return nil
+}
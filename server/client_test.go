@@ -0,0 +1,121 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+)
+
+func TestClientMetadata(t *testing.T) {
+	tsrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testServer(t, w, r)
+	}))
+	defer tsrv.Close()
+	e := httpexpect.New(t, tsrv.URL)
+
+	clientStore := store.NewClientStore()
+	clientStore.Set(clientID, &models.Client{
+		ID:                      clientID,
+		Secret:                  clientSecret,
+		Domain:                  "http://localhost",
+		RequirePKCE:             true,
+		TokenEndpointAuthMethod: oauth2.ClientSecretPost,
+	})
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	mgr.MapClientStorage(clientStore)
+
+	srv = server.NewDefaultServer(mgr)
+	srv.SetClientInfoHandler(func(r *http.Request) (string, string, error) {
+		if id, secret, ok := r.BasicAuth(); ok {
+			return id, secret, nil
+		}
+		return server.ClientFormHandler(r)
+	})
+
+	r := httptest.NewRequest("GET", "/authorize?response_type=code&client_id="+clientID, nil)
+	if _, err := srv.ValidationAuthorizeRequest(r); err != errors.ErrCodeChallengeRquired {
+		t.Errorf("the code challenge isn't required: %v", err)
+	}
+
+	// the client secret must be presented in the request body
+	e.POST("/token").
+		WithFormField("grant_type", "client_credentials").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	e.POST("/token").
+		WithFormField("grant_type", "client_credentials").
+		WithFormField("client_id", clientID).
+		WithFormField("client_secret", clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("access_token").String().NotEmpty()
+}
+
+func TestStrictRedirectURI(t *testing.T) {
+	asrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := srv.HandleAuthorizeRequest(w, r); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}))
+	defer asrv.Close()
+	e := httpexpect.WithConfig(httpexpect.Config{
+		BaseURL:  asrv.URL,
+		Reporter: httpexpect.NewAssertReporter(t),
+		Client: &http.Client{
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	})
+
+	clientStore := store.NewClientStore()
+	clientStore.Set(clientID, &models.Client{
+		ID:           clientID,
+		Secret:       clientSecret,
+		RedirectURIs: []string{"https://example.com/cb", "http://127.0.0.1/cb"},
+	})
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	mgr.MapClientStorage(clientStore)
+
+	srv = server.NewDefaultServer(mgr)
+	srv.SetUserAuthorizationHandler(func(w http.ResponseWriter, r *http.Request) (string, error) {
+		return "000000", nil
+	})
+
+	e.GET("/authorize").
+		WithQuery("response_type", "code").
+		WithQuery("client_id", clientID).
+		WithQuery("redirect_uri", "http://127.0.0.1:51004/cb").
+		Expect().
+		Status(http.StatusFound).
+		Header("Location").Contains("code=")
+
+	// the error of the unregistered redirect uri isn't redirected to it
+	e.GET("/authorize").
+		WithQuery("response_type", "code").
+		WithQuery("client_id", clientID).
+		WithQuery("redirect_uri", "https://example.com.evil.com/cb").
+		Expect().
+		Status(http.StatusBadRequest)
+
+	// several redirect uris are registered
+	e.GET("/authorize").
+		WithQuery("response_type", "code").
+		WithQuery("client_id", clientID).
+		Expect().
+		Status(http.StatusBadRequest)
+}
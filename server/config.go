@@ -15,6 +15,22 @@ type Config struct {
 	AllowedGrantTypes           []oauth2.GrantType    // allow the grant type
 	AllowedCodeChallengeMethods []oauth2.CodeChallengeMethod
 	ForcePKCE                   bool
+	Issuer                      string   // the issuer identifier of the authorization server
+	AuthorizeEndpoint           string   // the URL of the authorization endpoint
+	TokenEndpoint               string   // the URL of the token endpoint
+	RevocationEndpoint          string   // the URL of the revocation endpoint
+	IntrospectionEndpoint       string   // the URL of the introspection endpoint
+	JWKSURI                     string   // the URL of the json web key set document
+	DeviceAuthorizationEndpoint string   // the URL of the device authorization endpoint
+	DeviceVerificationURI       string   // the end-user verification URI of the device authorization grant
+	PushedAuthorizeEndpoint     string   // the URL of the pushed authorization request endpoint
+	RegistrationEndpoint        string   // the URL of the client registration endpoint
+	RequirePushedAuthorize      bool     // to require the authorization requests are pushed before
+	TokenEndpointAuthMethods    []string // the client authentication methods accepted by the client info handler
+	TLSClientCertificateHeader  string   // the header a trusted proxy forwards the client certificate in, when the TLS is terminated upstream
+	TrustedProxies              []string // the CIDRs of the proxies trusted to forward the client certificate header
+	BaseURL                     string   // the external base URL the requests are received at, when the server is behind a proxy
+	Audience                    string   // the audience of the resource server, the access tokens not issued to it are rejected
 }
 
 // NewConfig create to configuration instance
@@ -32,19 +48,25 @@ func NewConfig() *Config {
 			oauth2.CodeChallengePlain,
 			oauth2.CodeChallengeS256,
 		},
+		TokenEndpointAuthMethods: []string{"client_secret_basic"},
 	}
 }
 
 // AuthorizeRequest authorization request
 type AuthorizeRequest struct {
-	ResponseType        oauth2.ResponseType
-	ClientID            string
-	Scope               string
-	RedirectURI         string
-	State               string
-	UserID              string
-	CodeChallenge       string
-	CodeChallengeMethod oauth2.CodeChallengeMethod
-	AccessTokenExp      time.Duration
-	Request             *http.Request
+	ResponseType         oauth2.ResponseType
+	ClientID             string
+	Scope                string
+	RedirectURI          string
+	State                string
+	UserID               string
+	CodeChallenge        string
+	CodeChallengeMethod  oauth2.CodeChallengeMethod
+	Nonce                string
+	AuthTime             time.Time
+	RequestURI           string
+	Resource             []string
+	AuthorizationDetails oauth2.AuthorizationDetails
+	AccessTokenExp       time.Duration
+	Request              *http.Request
 }
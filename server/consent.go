@@ -0,0 +1,66 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// get the requested scope the user hasn't granted to the client yet,
+// covered is true when the consent covers the whole scope,
+// errors.ErrInvalidConsent is returned when the scope of the recorded consent is malformed
+func unconsentedScope(ci oauth2.ConsentInfo, scope string, match oauth2.ScopeMatcher) (string, bool, error) {
+	if ci == nil {
+		return scope, false, nil
+	}
+
+	granted, err := oauth2.ParseScope(ci.GetScope())
+	if err != nil {
+		return "", false, errors.ErrInvalidConsent
+	}
+	requested, err := oauth2.ParseScope(scope)
+	if err != nil {
+		return "", false, err
+	}
+
+	missing := oauth2.Scope{}
+	for _, v := range requested {
+		if !granted.Implies(v, match) {
+			missing = append(missing, v)
+		}
+	}
+	return missing.String(), len(missing) == 0, nil
+}
+
+// ask the user to consent the authorization request, the consent step is skipped
+// when the scope is covered by the recorded consent of the user to the client,
+// the authorization details are never remembered so the user is always asked for them
+func (s *Server) userConsent(w http.ResponseWriter, r *http.Request, req *AuthorizeRequest, fn UserConsentHandler) (bool, error) {
+	ctx := r.Context()
+
+	cm, ok := s.Manager.(oauth2.ConsentManager)
+	if !ok {
+		return fn(w, r, req.ClientID, req.Scope)
+	}
+
+	ci, err := cm.LoadConsent(ctx, req.UserID, req.ClientID)
+	if err != nil {
+		return false, err
+	}
+	scope, covered, err := unconsentedScope(ci, req.Scope, s.scopeMatcher())
+	if err != nil {
+		return false, err
+	} else if covered && len(req.AuthorizationDetails) == 0 {
+		return true, nil
+	}
+
+	consented, err := fn(w, r, req.ClientID, scope)
+	if err != nil || !consented {
+		return false, err
+	}
+	if _, err := cm.GrantConsent(ctx, req.UserID, req.ClientID, req.Scope); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,100 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+)
+
+func TestUserConsent(t *testing.T) {
+	tsrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testServer(t, w, r)
+	}))
+	defer tsrv.Close()
+	e := httpexpect.New(t, tsrv.URL)
+
+	var redirected []string
+	csrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = append(redirected, r.FormValue("error"))
+	}))
+	defer csrv.Close()
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	cstore, err := store.NewMemoryConsentStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mgr.MapConsentStorage(cstore)
+	mgr.MapClientStorage(clientStore(csrv.URL+"/oauth2", true))
+
+	var asked []string
+	deny := false
+	srv = server.NewDefaultServer(mgr)
+	srv.SetUserAuthorizationHandler(func(w http.ResponseWriter, r *http.Request) (string, error) {
+		return "000000", nil
+	})
+	srv.SetUserConsentHandler(func(w http.ResponseWriter, r *http.Request, clientID, scope string) (bool, error) {
+		asked = append(asked, scope)
+		if deny {
+			return false, errors.ErrAccessDenied
+		}
+		return true, nil
+	})
+
+	authorize := func(scope string) {
+		e.GET("/authorize").
+			WithQuery("response_type", "code").
+			WithQuery("client_id", clientID).
+			WithQuery("scope", scope).
+			WithQuery("redirect_uri", csrv.URL+"/oauth2").
+			Expect().Status(http.StatusOK)
+	}
+
+	authorize("read write")
+	authorize("read")
+	authorize("read profile")
+	if len(asked) != 2 || asked[0] != "read write" || asked[1] != "profile" {
+		t.Fatalf("unexpected consent requests: %v", asked)
+	}
+
+	ci, err := mgr.LoadConsent(context.Background(), "000000", clientID)
+	if err != nil || ci == nil || ci.GetScope() != "read write profile" {
+		t.Fatalf("unexpected consent: %v %v", ci, err)
+	}
+
+	// the withdrawn consent is asked again
+	if err := mgr.RevokeConsent(context.Background(), "000000", clientID); err != nil {
+		t.Fatal(err)
+	}
+	deny = true
+	authorize("read")
+	if len(asked) != 3 || redirected[len(redirected)-1] != "access_denied" {
+		t.Fatalf("unexpected consent requests: %v %v", asked, redirected)
+	}
+
+	cis, err := mgr.ListConsents(context.Background(), "000000")
+	if err != nil || len(cis) != 0 {
+		t.Fatalf("unexpected consents: %v %v", cis, err)
+	}
+
+	// the malformed scope of the recorded consent isn't ignored
+	ci = &models.Consent{UserID: "000000", ClientID: clientID, Scope: `read "write"`, CreateAt: time.Now()}
+	if err := cstore.Save(context.Background(), ci); err != nil {
+		t.Fatal(err)
+	}
+	deny = false
+	authorize("read")
+	if len(asked) != 3 || redirected[len(redirected)-1] != "server_error" {
+		t.Fatalf("unexpected consent requests: %v %v", asked, redirected)
+	}
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// get the device authorization management of the manager
+func (s *Server) deviceManager() (oauth2.DeviceAuthorizationManager, error) {
+	dm, ok := s.Manager.(oauth2.DeviceAuthorizationManager)
+	if !ok {
+		return nil, errors.ErrUnsupportedGrantType
+	}
+	return dm, nil
+}
+
+// GetDeviceAuthorizationData device authorization response data
+func (s *Server) GetDeviceAuthorizationData(di oauth2.DeviceCodeInfo) map[string]interface{} {
+	data := map[string]interface{}{
+		"device_code": di.GetDeviceCode(),
+		"user_code":   di.GetUserCode(),
+		"expires_in":  int64(di.GetExpiresIn().Seconds()),
+		"interval":    int64(di.GetInterval().Seconds()),
+	}
+
+	if uri := s.Config.DeviceVerificationURI; uri != "" {
+		data["verification_uri"] = uri
+		if u, err := url.Parse(uri); err == nil {
+			q := u.Query()
+			q.Set("user_code", di.GetUserCode())
+			u.RawQuery = q.Encode()
+			data["verification_uri_complete"] = u.String()
+		}
+	}
+	return data
+}
+
+// HandleDeviceAuthorizationRequest the device authorization request handling
+// https://tools.ietf.org/html/rfc8628#section-3.1
+func (s *Server) HandleDeviceAuthorizationRequest(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	if r.Method != "POST" {
+		return s.tokenError(w, errors.ErrInvalidRequest)
+	}
+
+	cli, err := s.authenticateClient(r)
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+
+	if !s.CheckGrantType(oauth2.DeviceCode) {
+		return s.tokenError(w, errors.ErrUnauthorizedClient)
+	}
+	if fn := s.ClientAuthorizedHandler; fn != nil {
+		allowed, err := fn(cli.GetID(), oauth2.DeviceCode)
+		if err != nil {
+			return s.tokenError(w, err)
+		} else if !allowed {
+			return s.tokenError(w, errors.ErrUnauthorizedClient)
+		}
+	}
+
+	scope, err := s.normalizeScope(r.FormValue("scope"))
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+
+	tgr := &oauth2.TokenGenerateRequest{
+		ClientID: cli.GetID(),
+		Scope:    scope,
+		Request:  r,
+	}
+	if fn := s.ClientScopeHandler; fn != nil {
+		allowed, err := fn(tgr)
+		if err != nil {
+			return s.tokenError(w, err)
+		} else if !allowed {
+			return s.tokenError(w, errors.ErrInvalidScope)
+		}
+	}
+
+	dm, err := s.deviceManager()
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+
+	di, err := dm.GenerateDeviceCode(ctx, tgr)
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+
+	return s.token(w, s.GetDeviceAuthorizationData(di), nil)
+}
+
+// HandleDeviceVerificationRequest the end-user verification request handling,
+// the user code is bound to the user of the user authorization handler,
+// and the handler returning the access denied error denies the device authorization,
+// the approval or the denial must be posted by the user
+// https://tools.ietf.org/html/rfc8628#section-3.3
+func (s *Server) HandleDeviceVerificationRequest(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	userCode := r.FormValue("user_code")
+	if userCode == "" {
+		return s.tokenError(w, errors.ErrInvalidRequest)
+	}
+
+	dm, err := s.deviceManager()
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+
+	if _, err := dm.LoadDeviceCode(ctx, userCode); err != nil {
+		if err == errors.ErrInvalidUserCode {
+			return s.tokenError(w, errors.ErrInvalidGrant)
+		}
+		return s.tokenError(w, err)
+	}
+
+	userID, err := s.UserAuthorizationHandler(w, r)
+	if err != nil && err != errors.ErrAccessDenied {
+		return s.tokenError(w, err)
+	} else if err == nil && userID == "" {
+		return nil
+	}
+
+	// the decision is only taken from the form post, so a link can't approve or deny the device authorization
+	if r.Method != http.MethodPost {
+		return s.tokenError(w, errors.ErrInvalidRequest)
+	}
+
+	if err == errors.ErrAccessDenied {
+		if err := dm.AuthorizeDeviceCode(ctx, userCode, "", false); err != nil {
+			return s.tokenError(w, err)
+		}
+		return s.tokenError(w, errors.ErrAccessDenied)
+	}
+
+	if err := dm.AuthorizeDeviceCode(ctx, userCode, userID, true); err != nil {
+		return s.tokenError(w, err)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
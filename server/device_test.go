@@ -0,0 +1,139 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+)
+
+func TestDeviceAuthorization(t *testing.T) {
+	tsrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testServer(t, w, r)
+	}))
+	defer tsrv.Close()
+	e := httpexpect.New(t, tsrv.URL)
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	mgr.MustDeviceCodeStorage(store.NewMemoryDeviceCodeStore())
+	mgr.MapClientStorage(clientStore("", true))
+	mgr.SetDeviceCodeInterval(time.Second)
+
+	cfg := server.NewConfig()
+	cfg.AllowedGrantTypes = append(cfg.AllowedGrantTypes, oauth2.DeviceCode)
+	cfg.DeviceVerificationURI = "https://example.com/device"
+	srv = server.NewServer(cfg, mgr)
+	srv.SetClientInfoHandler(server.ClientFormHandler)
+
+	approved := true
+	srv.SetUserAuthorizationHandler(func(w http.ResponseWriter, r *http.Request) (string, error) {
+		if !approved {
+			return "", errors.ErrAccessDenied
+		}
+		return "000000", nil
+	})
+
+	resObj := e.POST("/device_authorization").
+		WithFormField("client_id", clientID).
+		WithFormField("scope", "all").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	resObj.Value("verification_uri").Equal("https://example.com/device")
+	resObj.Value("interval").Equal(1)
+	deviceCode := resObj.Value("device_code").String().Raw()
+	userCode := resObj.Value("user_code").String().Raw()
+	resObj.Value("verification_uri_complete").Equal("https://example.com/device?user_code=" + userCode)
+
+	poll := func(code string) *httpexpect.Object {
+		return e.POST("/token").
+			WithFormField("grant_type", string(oauth2.DeviceCode)).
+			WithFormField("device_code", code).
+			WithFormField("client_id", clientID).
+			Expect().
+			JSON().Object()
+	}
+
+	poll(deviceCode).Value("error").Equal("authorization_pending")
+	poll(deviceCode).Value("error").Equal("slow_down")
+	poll("unknown").Value("error").Equal("invalid_grant")
+
+	e.POST("/device").
+		WithFormField("user_code", "unknown").
+		Expect().
+		Status(http.StatusUnauthorized).
+		JSON().Object().Value("error").Equal("invalid_grant")
+
+	// the user code is case insensitive and the punctuation is ignored
+	e.POST("/device").
+		WithFormField("user_code", userCode[:4]+"-"+userCode[4:]).
+		Expect().
+		Status(http.StatusOK)
+
+	resObj = poll(deviceCode)
+	resObj.Value("access_token").String().NotEmpty()
+	resObj.Value("refresh_token").String().NotEmpty()
+	resObj.Value("scope").Equal("all")
+
+	// the device code is concluded after the token is issued
+	poll(deviceCode).Value("error").Equal("invalid_grant")
+
+	approved = false
+	resObj = e.POST("/device_authorization").
+		WithFormField("client_id", clientID).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	deviceCode = resObj.Value("device_code").String().Raw()
+
+	userCode = resObj.Value("user_code").String().Raw()
+
+	// the link can't deny the device authorization
+	e.GET("/device").
+		WithQuery("user_code", userCode).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Equal("invalid_request")
+
+	e.POST("/device").
+		WithFormField("user_code", userCode).
+		Expect().
+		Status(http.StatusForbidden).
+		JSON().Object().Value("error").Equal("access_denied")
+
+	poll(deviceCode).Value("error").Equal("access_denied")
+
+	// the user code colliding with the pending device authorization is generated again
+	pending := e.POST("/device_authorization").
+		WithFormField("client_id", clientID).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("user_code").String().Raw()
+
+	mgr.MapDeviceCodeGenerate(&userCodeGenerate{codes: []string{pending, "BCDFGHJK"}})
+	e.POST("/device_authorization").
+		WithFormField("client_id", clientID).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("user_code").Equal("BCDFGHJK")
+}
+
+// generate the listed user codes in order
+type userCodeGenerate struct {
+	codes []string
+}
+
+func (g *userCodeGenerate) Token(ctx context.Context, data *oauth2.GenerateBasic) (string, string, error) {
+	v := g.codes[0]
+	g.codes = g.codes[1:]
+	return "device_" + v, v, nil
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DPoPTokenType the token type of the DPoP-bound access token
+const DPoPTokenType = "DPoP"
+
+// the DPoP proof is accepted within the lifetime around its issue time
+const dpopProofLifetime = time.Minute * 5
+
+// the asymmetric signing algorithms of the DPoP proof
+var dpopSigningMethods = []string{
+	"RS256", "RS384", "RS512", "PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512", "EdDSA",
+}
+
+// DPoPProofClaims the claims of the DPoP proof jwt
+type DPoPProofClaims struct {
+	jwt.RegisteredClaims
+	HTM string `json:"htm"`
+	HTU string `json:"htu"`
+	ATH string `json:"ath,omitempty"`
+}
+
+// get the URL of the request without the query and fragment, the request received
+// behind a proxy is identified by the external base URL of the server
+func (s *Server) requestURL(r *http.Request) string {
+	if base := s.Config.BaseURL; base != "" {
+		u, err := url.Parse(base)
+		if err == nil {
+			return strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host) + strings.TrimSuffix(u.Path, "/") + r.URL.Path
+		}
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + strings.ToLower(r.Host) + r.URL.Path
+}
+
+// check the htu claim of the DPoP proof identifies the request URL
+func (s *Server) validHTU(htu string, r *http.Request) bool {
+	u, err := url.Parse(htu)
+	if err != nil {
+		return false
+	}
+	return strings.ToLower(u.Scheme)+"://"+strings.ToLower(u.Host)+u.Path == s.requestURL(r)
+}
+
+// verify the DPoP proof of the request, and return the thumbprint of the proof key,
+// the empty thumbprint means the request has no DPoP proof
+// https://tools.ietf.org/html/rfc9449#section-4.3
+func (s *Server) verifyDPoPProof(r *http.Request, accessToken string) (string, error) {
+	proofs := r.Header.Values("DPoP")
+	if len(proofs) == 0 {
+		return "", nil
+	} else if len(proofs) > 1 {
+		return "", errors.ErrInvalidDPoPProof
+	}
+
+	jwk := &models.JWK{}
+	claims := &DPoPProofClaims{}
+	_, err := jwt.ParseWithClaims(proofs[0], claims, func(t *jwt.Token) (interface{}, error) {
+		if typ, _ := t.Header["typ"].(string); typ != "dpop+jwt" {
+			return nil, errors.ErrInvalidDPoPProof
+		}
+		header, ok := t.Header["jwk"].(map[string]interface{})
+		if !ok {
+			return nil, errors.ErrInvalidDPoPProof
+		} else if _, ok := header["d"]; ok {
+			// the private key must never be sent
+			return nil, errors.ErrInvalidDPoPProof
+		}
+		jv, err := json.Marshal(header)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(jv, jwk); err != nil {
+			return nil, err
+		}
+		return jwk.PublicKey()
+	}, jwt.WithValidMethods(dpopSigningMethods))
+	if err != nil {
+		return "", errors.ErrInvalidDPoPProof
+	}
+
+	if claims.ID == "" || claims.HTM != r.Method || !s.validHTU(claims.HTU, r) || claims.IssuedAt == nil {
+		return "", errors.ErrInvalidDPoPProof
+	}
+	if iat := claims.IssuedAt.Time; time.Since(iat) > dpopProofLifetime || time.Until(iat) > dpopProofLifetime {
+		return "", errors.ErrInvalidDPoPProof
+	}
+
+	if accessToken != "" {
+		ath := sha256.Sum256([]byte(accessToken))
+		if claims.ATH != base64.RawURLEncoding.EncodeToString(ath[:]) {
+			return "", errors.ErrInvalidDPoPProof
+		}
+	}
+
+	jkt, err := jwk.Thumbprint()
+	if err != nil {
+		return "", errors.ErrInvalidDPoPProof
+	}
+
+	if err := s.useJTI(r.Context(), "dpop "+jkt, claims.ID, claims.IssuedAt.Add(dpopProofLifetime), errors.ErrInvalidDPoPProof); err != nil {
+		return "", err
+	}
+	return jkt, nil
+}
+
+// get the key confirmation of the sender-constrained token
+func tokenConfirmation(ti oauth2.TokenInfo) *oauth2.Confirmation {
+	if bti, ok := ti.(oauth2.BoundTokenInfo); ok {
+		return bti.GetConfirmation()
+	}
+	return nil
+}
+
+// check the refresh token bound to the key or the certificate of a public client is refreshed with the same one
+func (s *Server) validBoundRefresh(ctx context.Context, tgr *oauth2.TokenGenerateRequest, rti oauth2.TokenInfo) error {
+	cnf := tokenConfirmation(rti)
+	if cnf == nil || (cnf.JKT == "" && cnf.X5TS256 == "") {
+		return nil
+	}
+	cli, err := s.Manager.GetClient(ctx, rti.GetClientID())
+	if err != nil {
+		return err
+	} else if !cli.IsPublic() {
+		return nil
+	}
+
+	bound := tgr.Confirmation
+	if bound == nil ||
+		(cnf.JKT != "" && bound.JKT != cnf.JKT) ||
+		(cnf.X5TS256 != "" && bound.X5TS256 != cnf.X5TS256) {
+		return errors.ErrInvalidGrant
+	}
+	return nil
+}
+
+// ValidationDPoPToken validate the DPoP-bound access token of the request and its DPoP proof
+// https://tools.ietf.org/html/rfc9449#section-7
+func (s *Server) ValidationDPoPToken(r *http.Request) (oauth2.TokenInfo, error) {
+	ctx := r.Context()
+
+	auth := r.Header.Get("Authorization")
+	prefix := DPoPTokenType + " "
+	if len(auth) <= len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+		return nil, errors.ErrInvalidAccessToken
+	}
+	accessToken := auth[len(prefix):]
+
+	jkt, err := s.verifyDPoPProof(r, accessToken)
+	if err != nil {
+		return nil, err
+	} else if jkt == "" {
+		return nil, errors.ErrInvalidDPoPProof
+	}
+
+	ti, err := s.Manager.LoadAccessToken(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if cnf := tokenConfirmation(ti); cnf == nil || cnf.JKT != jkt {
+		return nil, errors.ErrInvalidAccessToken
+	} else if err := s.validCertificateBinding(r, cnf); err != nil {
+		return nil, err
+	} else if err := s.validTokenAudience(ti); err != nil {
+		return nil, err
+	}
+	return ti, nil
+}
@@ -0,0 +1,199 @@
+package server_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect"
+	"github.com/go-oauth2/oauth2/v4/generates"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestDPoP(t *testing.T) {
+	dsrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.Path {
+		case "/token":
+			err = srv.HandleTokenRequest(w, r)
+		case "/introspect":
+			err = srv.HandleIntrospectionRequest(w, r)
+		case "/resource":
+			_, err = srv.ValidationDPoPToken(r)
+		case "/bearer":
+			_, err = srv.ValidationBearerToken(r)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		}
+	}))
+	defer dsrv.Close()
+	e := httpexpect.New(t, dsrv.URL)
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	mgr.MapClientStorage(clientStore("", false))
+	mgr.MapAccessGenerate(generates.NewJWTAccessGenerate("", []byte("00000000"), jwt.SigningMethodHS256))
+
+	srv = server.NewDefaultServer(mgr)
+	jstore, err := store.NewMemoryJTIStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.SetJTIStore(jstore)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwk, err := models.NewJWK("", "ES256", key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	jkt, err := jwk.Thumbprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := dsrv.URL
+	proof := func(method, path, accessToken string) string {
+		claims := &server.DPoPProofClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:       uuid.Must(uuid.NewRandom()).String(),
+				IssuedAt: jwt.NewNumericDate(time.Now()),
+			},
+			HTM: method,
+			HTU: base + path,
+		}
+		if accessToken != "" {
+			ath := sha256.Sum256([]byte(accessToken))
+			claims.ATH = base64.RawURLEncoding.EncodeToString(ath[:])
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+		token.Header["typ"] = "dpop+jwt"
+		token.Header["jwk"] = jwk
+		v, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	e.POST("/token").
+		WithHeader("DPoP", proof("GET", "/token", "")).
+		WithFormField("grant_type", "client_credentials").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Equal("invalid_dpop_proof")
+
+	resObj := e.POST("/token").
+		WithHeader("DPoP", proof("POST", "/token", "")).
+		WithFormField("grant_type", "client_credentials").
+		WithFormField("scope", "all").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	resObj.Value("token_type").Equal("DPoP")
+	access := resObj.Value("access_token").String().Raw()
+
+	claims := &generates.JWTAccessClaims{}
+	_, err = jwt.ParseWithClaims(access, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte("00000000"), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Confirmation == nil || claims.Confirmation.JKT != jkt {
+		t.Errorf("unexpected cnf claim: %+v", claims.Confirmation)
+	}
+
+	resObj = e.POST("/introspect").
+		WithFormField("token", access).
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	resObj.Value("token_type").Equal("DPoP")
+	resObj.Value("cnf").Object().Value("jkt").Equal(jkt)
+
+	resourceProof := proof("GET", "/resource", access)
+	e.GET("/resource").
+		WithHeader("Authorization", "DPoP "+access).
+		WithHeader("DPoP", resourceProof).
+		Expect().
+		Status(http.StatusOK)
+
+	// replayed proof
+	e.GET("/resource").
+		WithHeader("Authorization", "DPoP "+access).
+		WithHeader("DPoP", resourceProof).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	// the replay can't be detected without the jwt id store
+	srv.SetJTIStore(nil)
+	e.GET("/resource").
+		WithHeader("Authorization", "DPoP "+access).
+		WithHeader("DPoP", proof("GET", "/resource", access)).
+		Expect().
+		Status(http.StatusUnauthorized)
+	srv.SetJTIStore(jstore)
+
+	// proof without the access token hash
+	e.GET("/resource").
+		WithHeader("Authorization", "DPoP "+access).
+		WithHeader("DPoP", proof("GET", "/resource", "")).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	// the proof of the request received behind a TLS-terminating proxy identifies the external URL
+	srv.Config.BaseURL = "https://as.example.com/"
+	e.GET("/resource").
+		WithHeader("Authorization", "DPoP "+access).
+		WithHeader("DPoP", proof("GET", "/resource", access)).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	base = "https://AS.example.com"
+	e.GET("/resource").
+		WithHeader("Authorization", "DPoP "+access).
+		WithHeader("DPoP", proof("GET", "/resource", access)).
+		Expect().
+		Status(http.StatusOK)
+	srv.Config.BaseURL, base = "", dsrv.URL
+
+	// proof of a different key
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key = other
+	jwk, err = models.NewJWK("", "ES256", key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.GET("/resource").
+		WithHeader("Authorization", "DPoP "+access).
+		WithHeader("DPoP", proof("GET", "/resource", access)).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	// the DPoP-bound access token is not a bearer token
+	e.GET("/bearer").
+		WithHeader("Authorization", "Bearer "+access).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
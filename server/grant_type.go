@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// the handlers of the custom grant type
+type grantHandler struct {
+	request GrantRequestHandler
+	token   GrantTokenHandler
+}
+
+// RegisterGrantType register the custom grant type with its request parser and token handler,
+// the grant type is allowed by the server and the access token is generated by the manager
+func (s *Server) RegisterGrantType(gt oauth2.GrantType, request GrantRequestHandler, token GrantTokenHandler) {
+	oauth2.RegisterGrantType(gt)
+
+	if s.grantHandlers == nil {
+		s.grantHandlers = make(map[oauth2.GrantType]*grantHandler)
+	}
+	s.grantHandlers[gt] = &grantHandler{request: request, token: token}
+
+	if !s.CheckGrantType(gt) {
+		s.Config.AllowedGrantTypes = append(s.Config.AllowedGrantTypes, gt)
+	}
+}
+
+// generate the access token of the custom grant type
+func (s *Server) generateGrantToken(ctx context.Context, gt oauth2.GrantType, tgr *oauth2.TokenGenerateRequest, h *grantHandler) (oauth2.TokenInfo, error) {
+	if fn := s.ClientScopeHandler; fn != nil {
+		allowed, err := fn(tgr)
+		if err != nil {
+			return nil, err
+		} else if !allowed {
+			return nil, errors.ErrInvalidScope
+		}
+	}
+
+	if fn := h.token; fn != nil {
+		if err := fn(ctx, tgr); err != nil {
+			return nil, err
+		}
+	}
+	return s.Manager.GenerateAccessToken(ctx, gt, tgr)
+}
@@ -0,0 +1,91 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+)
+
+func TestCustomGrantType(t *testing.T) {
+	tsrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testServer(t, w, r)
+	}))
+	defer tsrv.Close()
+	e := httpexpect.New(t, tsrv.URL)
+
+	otp := oauth2.GrantType("urn:example:params:oauth:grant-type:otp")
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	mgr.MapClientStorage(clientStore("", false))
+	mgr.SetGrantTokenCfg(otp, &manage.Config{AccessTokenExp: time.Minute * 10})
+
+	srv = server.NewDefaultServer(mgr)
+	srv.RegisterGrantType(otp, func(r *http.Request, tgr *oauth2.TokenGenerateRequest) error {
+		if r.FormValue("otp") != "123456" {
+			return errors.ErrInvalidGrant
+		}
+		tgr.UserID = "000000"
+		tgr.Scope = r.FormValue("scope")
+		return nil
+	}, func(ctx context.Context, tgr *oauth2.TokenGenerateRequest) error {
+		if tgr.Scope == "" {
+			tgr.Scope = "read"
+		}
+		return nil
+	})
+
+	e.POST("/token").
+		WithFormField("grant_type", string(otp)).
+		WithFormField("otp", "000000").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusUnauthorized).
+		JSON().Object().Value("error").Equal("invalid_grant")
+
+	resObj := e.POST("/token").
+		WithFormField("grant_type", string(otp)).
+		WithFormField("otp", "123456").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	resObj.Value("scope").Equal("read")
+	resObj.Value("expires_in").Equal(600)
+
+	ti, err := mgr.LoadAccessToken(context.Background(), resObj.Value("access_token").String().Raw())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ti.GetUserID() != "000000" {
+		t.Errorf("unexpected user id: %s", ti.GetUserID())
+	}
+
+	w := httptest.NewRecorder()
+	if err := srv.HandleMetadataRequest(w, httptest.NewRequest(http.MethodGet, server.MetadataPath, nil)); err != nil {
+		t.Fatal(err)
+	}
+	var data struct {
+		GrantTypes []string `json:"grant_types_supported"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &data); err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, v := range data.GrantTypes {
+		found = found || v == string(otp)
+	}
+	if !found {
+		t.Errorf("custom grant type is not in the metadata: %v", data.GrantTypes)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-oauth2/oauth2/v4"
 	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
 )
 
 type (
@@ -17,12 +18,22 @@ type (
 	// ClientAuthorizedHandler check the client allows to use this authorization grant type
 	ClientAuthorizedHandler func(clientID string, grant oauth2.GrantType) (allowed bool, err error)
 
-	// ClientScopeHandler check the client allows to use scope
+	// ClientScopeHandler check the client allows to use scope,
+	// the scope of the request is normalized and oauth2.ParseScope splits it into the scope tokens
 	ClientScopeHandler func(tgr *oauth2.TokenGenerateRequest) (allowed bool, err error)
 
 	// UserAuthorizationHandler get user id from request authorization
 	UserAuthorizationHandler func(w http.ResponseWriter, r *http.Request) (userID string, err error)
 
+	// UserConsentHandler ask the user to consent the scope not granted to the client yet,
+	// the handler returns false when it responds itself, e.g. by showing the consent page,
+	// and errors.ErrAccessDenied when the user denies the request
+	UserConsentHandler func(w http.ResponseWriter, r *http.Request, clientID, scope string) (consented bool, err error)
+
+	// AuthorizationDetailHandler validate the authorization details object of the registered type requested by the client,
+	// errors.ErrInvalidAuthorizationDetails is returned for the object the client isn't allowed to request
+	AuthorizationDetailHandler func(ctx context.Context, clientID string, detail oauth2.AuthorizationDetail) error
+
 	// PasswordAuthorizationHandler get user id from username and password
 	PasswordAuthorizationHandler func(ctx context.Context, clientID, username, password string) (userID string, err error)
 
@@ -32,6 +43,32 @@ type (
 	// RefreshingValidationHandler check if refresh_token is still valid. eg no revocation or other
 	RefreshingValidationHandler func(ti oauth2.TokenInfo) (allowed bool, err error)
 
+	// TokenExchangeHandler check the token exchange is allowed,
+	// the handler decides the scope and audience of the new token by changing the token generate request
+	TokenExchangeHandler func(ctx context.Context, tgr *oauth2.TokenGenerateRequest, subject, actor oauth2.TokenInfo) (allowed bool, err error)
+
+	// IssuerKeysHandler get the public keys of the trusted jwt issuer, nil keys mean the issuer isn't trusted
+	IssuerKeysHandler func(ctx context.Context, issuer string) (keys *models.JWKSet, err error)
+
+	// ClientKeysHandler get the public keys of the client
+	ClientKeysHandler func(ctx context.Context, clientID string) (keys *models.JWKSet, err error)
+
+	// RequestURIFetchHandler fetch the request object referenced by the request uri
+	RequestURIFetchHandler func(ctx context.Context, requestURI string) (requestObject string, err error)
+
+	// AssertionUserHandler get user id from the subject of the jwt assertion
+	AssertionUserHandler func(ctx context.Context, clientID, issuer, subject string) (userID string, err error)
+
+	// RegistrationAccessHandler check the client registration request is authorized, e.g. by the initial access token
+	RegistrationAccessHandler func(r *http.Request) (allowed bool, err error)
+
+	// GrantRequestHandler parse the token request of the custom grant type into the token generate request
+	GrantRequestHandler func(r *http.Request, tgr *oauth2.TokenGenerateRequest) error
+
+	// GrantTokenHandler authorize the token generate request of the custom grant type before the access token is generated,
+	// the handler may resolve the user id or narrow the scope
+	GrantTokenHandler func(ctx context.Context, tgr *oauth2.TokenGenerateRequest) error
+
 	// ResponseErrorHandler response error handing
 	ResponseErrorHandler func(re *errors.Response)
 
@@ -119,3 +156,10 @@ func AccessTokenCookieResolveHandler(r *http.Request) (string, bool) {
 
 	return c.Value, true
 }
+
+// StaticIssuerKeysHandler trust the issuers of the map with their public keys
+func StaticIssuerKeysHandler(issuers map[string]*models.JWKSet) IssuerKeysHandler {
+	return func(ctx context.Context, issuer string) (*models.JWKSet, error) {
+		return issuers[issuer], nil
+	}
+}
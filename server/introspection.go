@@ -0,0 +1,103 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// HandleIntrospectionRequest the token introspection request handling
+// https://tools.ietf.org/html/rfc7662
+func (s *Server) HandleIntrospectionRequest(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	if r.Method != "POST" {
+		return s.tokenError(w, errors.ErrInvalidRequest)
+	}
+
+	if _, err := s.authenticateClient(r); err != nil {
+		return s.tokenError(w, err)
+	}
+
+	token := r.FormValue("token")
+	if token == "" {
+		return s.tokenError(w, errors.ErrInvalidRequest)
+	}
+
+	// the hint is only an optimization, unknown values are ignored
+	hint := r.FormValue("token_type_hint")
+	if hint != "access_token" && hint != "refresh_token" {
+		hint = ""
+	}
+
+	ti, isRefresh, err := s.loadToken(ctx, token, hint)
+	if err != nil {
+		return s.tokenError(w, err)
+	} else if ti == nil {
+		return s.token(w, map[string]interface{}{"active": false}, nil)
+	}
+
+	return s.token(w, s.GetIntrospectionData(ti, isRefresh), nil)
+}
+
+// GetIntrospectionData get the introspection response data of an active token
+func (s *Server) GetIntrospectionData(ti oauth2.TokenInfo, isRefresh bool) map[string]interface{} {
+	data := map[string]interface{}{
+		"active":    true,
+		"client_id": ti.GetClientID(),
+	}
+
+	if scope := ti.GetScope(); scope != "" {
+		data["scope"] = scope
+	}
+
+	if userID := ti.GetUserID(); userID != "" {
+		data["sub"] = userID
+	}
+
+	createAt, expiresIn := ti.GetAccessCreateAt(), ti.GetAccessExpiresIn()
+	if isRefresh {
+		createAt, expiresIn = ti.GetRefreshCreateAt(), ti.GetRefreshExpiresIn()
+	} else {
+		data["token_type"] = s.Config.TokenType
+		if cnf := tokenConfirmation(ti); cnf != nil && cnf.JKT != "" {
+			data["token_type"] = DPoPTokenType
+		}
+	}
+
+	if cnf := tokenConfirmation(ti); cnf != nil {
+		data["cnf"] = cnf
+	}
+
+	data["iat"] = createAt.Unix()
+	if expiresIn > 0 {
+		data["exp"] = createAt.Add(expiresIn).Unix()
+	}
+
+	if ati, ok := ti.(oauth2.AudienceTokenInfo); ok && len(ati.GetAudience()) > 0 {
+		data["aud"] = ati.GetAudience()
+	}
+
+	if dti, ok := ti.(oauth2.DelegatedTokenInfo); ok && dti.GetActor() != nil {
+		data["act"] = dti.GetActor()
+	}
+
+	if details := tokenAuthorizationDetails(ti); len(details) > 0 {
+		data["authorization_details"] = details
+	}
+
+	if eti, ok := ti.(oauth2.ExtendableTokenInfo); ok {
+		for k, v := range eti.GetExtension() {
+			if _, ok := data[k]; ok || len(v) == 0 {
+				continue
+			}
+			if len(v) == 1 {
+				data[k] = v[0]
+			} else {
+				data[k] = v
+			}
+		}
+	}
+	return data
+}
@@ -0,0 +1,86 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gavv/httpexpect"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+)
+
+func TestIntrospection(t *testing.T) {
+	tsrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testServer(t, w, r)
+	}))
+	defer tsrv.Close()
+	e := httpexpect.New(t, tsrv.URL)
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	mgr.MapClientStorage(clientStore("", false))
+	mgr.SetExtractExtensionHandler(func(tgr *oauth2.TokenGenerateRequest, ti oauth2.ExtendableTokenInfo) {
+		ti.SetExtension(url.Values{"tenant": {"acme"}})
+	})
+	srv = server.NewDefaultServer(mgr)
+	srv.SetPasswordAuthorizationHandler(func(_ context.Context, _, username, password string) (string, error) {
+		return "000000", nil
+	})
+
+	resObj := e.POST("/token").
+		WithFormField("grant_type", "password").
+		WithFormField("username", "admin").
+		WithFormField("password", "123456").
+		WithFormField("scope", "all").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	access := resObj.Value("access_token").String().Raw()
+	refresh := resObj.Value("refresh_token").String().Raw()
+
+	e.POST("/introspect").
+		WithFormField("token", access).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	obj := e.POST("/introspect").
+		WithFormField("token", access).
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	obj.Value("active").Equal(true)
+	obj.Value("scope").Equal("all")
+	obj.Value("client_id").Equal(clientID)
+	obj.Value("sub").Equal("000000")
+	obj.Value("token_type").Equal("Bearer")
+	obj.Value("tenant").Equal("acme")
+	obj.ContainsKey("exp").ContainsKey("iat")
+
+	obj = e.POST("/introspect").
+		WithFormField("token", refresh).
+		WithFormField("token_type_hint", "refresh_token").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	obj.Value("active").Equal(true)
+	obj.NotContainsKey("token_type")
+
+	if err := mgr.RemoveAccessToken(context.Background(), access); err != nil {
+		t.Fatal(err)
+	}
+
+	e.POST("/introspect").
+		WithFormField("token", access).
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Equal(map[string]interface{}{"active": false})
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// the used jwt ids kept in memory, the default jwt id store of the server
+type memoryJTIStore struct {
+	mu   sync.Mutex
+	used map[string]time.Time
+}
+
+func newMemoryJTIStore() *memoryJTIStore {
+	return &memoryJTIStore{used: make(map[string]time.Time)}
+}
+
+// Use mark the jwt id as used until the expiration time, fresh is false if it was used before
+func (js *memoryJTIStore) Use(ctx context.Context, jti string, exp time.Time) (bool, error) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	now := time.Now()
+	for k, v := range js.used {
+		if !v.After(now) {
+			delete(js.used, k)
+		}
+	}
+	if _, ok := js.used[jti]; ok {
+		return false, nil
+	}
+	js.used[jti] = exp
+	return true, nil
+}
+
+// mark the jwt id of the jwt as used until its expiration time, the jwt without jwt id,
+// the replayed jwt and the jwt verified without the jwt id store are rejected with the invalid error
+func (s *Server) useJTI(ctx context.Context, key, jti string, exp time.Time, invalid error) error {
+	if jti == "" || s.JTIStore == nil {
+		return invalid
+	}
+	fresh, err := s.JTIStore.Use(ctx, key+" "+jti, exp)
+	if err != nil {
+		return err
+	} else if !fresh {
+		return invalid
+	}
+	return nil
+}
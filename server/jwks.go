@@ -0,0 +1,41 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
+)
+
+// JWKSPath the well-known path of the json web key set document
+const JWKSPath = "/.well-known/jwks.json"
+
+// KeySet provide the public keys to verify the signed tokens,
+// e.g. the jwt access token generator or its key ring
+type KeySet interface {
+	JWKSet(ctx context.Context) (*models.JWKSet, error)
+}
+
+// HandleJWKSRequest the json web key set request handling,
+// it publishes the public keys of the key set to verify the signed tokens
+// https://tools.ietf.org/html/rfc7517#section-5
+func (s *Server) HandleJWKSRequest(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != "GET" {
+		return s.tokenError(w, errors.ErrInvalidRequest)
+	}
+
+	set := &models.JWKSet{Keys: []*models.JWK{}}
+	if ks := s.KeySet; ks != nil {
+		v, err := ks.JWKSet(r.Context())
+		if err != nil {
+			return s.tokenError(w, err)
+		}
+		set = v
+	}
+
+	w.Header().Set("Content-Type", "application/jwk-set+json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(set)
+}
@@ -0,0 +1,111 @@
+package server_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestJWTBearer(t *testing.T) {
+	tsrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testServer(t, w, r)
+	}))
+	defer tsrv.Close()
+	e := httpexpect.New(t, tsrv.URL)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwk, err := models.NewJWK("idp-1", "ES256", key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	mgr.MapClientStorage(clientStore("", false))
+
+	cfg := server.NewConfig()
+	cfg.AllowedGrantTypes = append(cfg.AllowedGrantTypes, oauth2.JWTBearer)
+	cfg.TokenEndpoint = "https://as.example.com/token"
+	srv = server.NewServer(cfg, mgr)
+	srv.SetIssuerKeysHandler(server.StaticIssuerKeysHandler(map[string]*models.JWKSet{
+		"https://idp.example.com": {Keys: []*models.JWK{jwk}},
+	}))
+	srv.SetAssertionUserHandler(func(ctx context.Context, clientID, issuer, subject string) (string, error) {
+		return "idp|" + subject, nil
+	})
+	assertion := func(iss, aud, jti string, exp time.Time) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+			Issuer:    iss,
+			Subject:   "000000",
+			Audience:  jwt.ClaimStrings{aud},
+			ExpiresAt: jwt.NewNumericDate(exp),
+			ID:        jti,
+		})
+		token.Header["kid"] = "idp-1"
+		v, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	request := func(assertion string) *httpexpect.Response {
+		return e.POST("/token").
+			WithFormField("grant_type", string(oauth2.JWTBearer)).
+			WithFormField("assertion", assertion).
+			WithFormField("scope", "all").
+			WithBasicAuth(clientID, clientSecret).
+			Expect()
+	}
+
+	exp := time.Now().Add(time.Minute)
+	valid := assertion("https://idp.example.com", cfg.TokenEndpoint, "jti-1", exp)
+
+	resObj := request(valid).Status(http.StatusOK).JSON().Object()
+	resObj.Value("access_token").String().NotEmpty()
+	resObj.NotContainsKey("refresh_token")
+
+	ti, err := mgr.LoadAccessToken(context.Background(), resObj.Value("access_token").String().Raw())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ti.GetUserID() != "idp|000000" {
+		t.Errorf("unexpected user id: %s", ti.GetUserID())
+	}
+
+	// replayed assertion
+	request(valid).Status(http.StatusUnauthorized).
+		JSON().Object().Value("error").Equal("invalid_grant")
+
+	request(assertion("https://idp.example.com", "https://other.example.com", "jti-2", exp)).
+		Status(http.StatusUnauthorized).JSON().Object().Value("error").Equal("invalid_grant")
+
+	request(assertion("https://untrusted.example.com", cfg.TokenEndpoint, "jti-3", exp)).
+		Status(http.StatusUnauthorized).JSON().Object().Value("error").Equal("invalid_grant")
+
+	request(assertion("https://idp.example.com", cfg.TokenEndpoint, "jti-4", time.Now().Add(-time.Minute))).
+		Status(http.StatusUnauthorized).JSON().Object().Value("error").Equal("invalid_grant")
+
+	request(assertion("https://idp.example.com", cfg.TokenEndpoint, "", exp)).
+		Status(http.StatusUnauthorized).JSON().Object().Value("error").Equal("invalid_grant")
+
+	// the replay can't be detected without the jwt id store
+	srv.SetJTIStore(nil)
+	request(assertion("https://idp.example.com", cfg.TokenEndpoint, "jti-5", exp)).
+		Status(http.StatusUnauthorized).JSON().Object().Value("error").Equal("invalid_grant")
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// MetadataPath the well-known path of the authorization server metadata
+const MetadataPath = "/.well-known/oauth-authorization-server"
+
+// GetMetadata get the authorization server metadata built from the configuration
+// https://tools.ietf.org/html/rfc8414#section-2
+func (s *Server) GetMetadata() map[string]interface{} {
+	data := make(map[string]interface{})
+
+	// the unset issuer and endpoints are omitted rather than published empty
+	endpoints := map[string]string{
+		"issuer":                                s.Config.Issuer,
+		"authorization_endpoint":                s.Config.AuthorizeEndpoint,
+		"token_endpoint":                        s.Config.TokenEndpoint,
+		"revocation_endpoint":                   s.Config.RevocationEndpoint,
+		"introspection_endpoint":                s.Config.IntrospectionEndpoint,
+		"jwks_uri":                              s.Config.JWKSURI,
+		"device_authorization_endpoint":         s.Config.DeviceAuthorizationEndpoint,
+		"pushed_authorization_request_endpoint": s.Config.PushedAuthorizeEndpoint,
+		"registration_endpoint":                 s.Config.RegistrationEndpoint,
+	}
+	for k, v := range endpoints {
+		if v != "" {
+			data[k] = v
+		}
+	}
+
+	if sr := s.ScopeRegistry; sr != nil {
+		data["scopes_supported"] = sr.Scopes()
+	}
+	if ar := s.AuthorizationDetailsRegistry; ar != nil {
+		data["authorization_details_types_supported"] = ar.Types()
+	}
+
+	responseTypes := make([]string, 0, len(s.Config.AllowedResponseTypes))
+	for _, rt := range s.Config.AllowedResponseTypes {
+		if rt.String() != "" {
+			responseTypes = append(responseTypes, rt.String())
+		}
+	}
+	data["response_types_supported"] = responseTypes
+
+	grantTypes := make([]string, 0, len(s.Config.AllowedGrantTypes)+1)
+	for _, gt := range s.Config.AllowedGrantTypes {
+		if gt.String() != "" {
+			grantTypes = append(grantTypes, gt.String())
+		}
+	}
+	if s.CheckResponseType(oauth2.Token) {
+		grantTypes = append(grantTypes, "implicit")
+	}
+	data["grant_types_supported"] = grantTypes
+
+	if len(s.Config.AllowedCodeChallengeMethods) > 0 {
+		methods := make([]string, 0, len(s.Config.AllowedCodeChallengeMethods))
+		for _, ccm := range s.Config.AllowedCodeChallengeMethods {
+			if ccm.String() != "" {
+				methods = append(methods, ccm.String())
+			}
+		}
+		data["code_challenge_methods_supported"] = methods
+	}
+
+	data["request_parameter_supported"] = true
+	data["request_uri_parameter_supported"] = s.RequestURIFetchHandler != nil
+
+	data["dpop_signing_alg_values_supported"] = dpopSigningMethods
+	data["tls_client_certificate_bound_access_tokens"] = true
+
+	if s.Config.RequirePushedAuthorize {
+		data["require_pushed_authorization_requests"] = true
+	}
+
+	if methods := s.Config.TokenEndpointAuthMethods; len(methods) > 0 {
+		data["token_endpoint_auth_methods_supported"] = methods
+		if s.Config.RevocationEndpoint != "" {
+			data["revocation_endpoint_auth_methods_supported"] = methods
+		}
+		if s.Config.IntrospectionEndpoint != "" {
+			data["introspection_endpoint_auth_methods_supported"] = methods
+		}
+
+		var algs []string
+		for _, m := range methods {
+			switch oauth2.ClientAuthMethod(m) {
+			case oauth2.PrivateKeyJWT:
+				algs = append(algs, privateKeyJWTSigningMethods...)
+			case oauth2.ClientSecretJWT:
+				algs = append(algs, clientSecretJWTSigningMethods...)
+			}
+		}
+		if len(algs) > 0 {
+			data["token_endpoint_auth_signing_alg_values_supported"] = algs
+		}
+	}
+
+	return data
+}
+
+// HandleMetadataRequest the authorization server metadata request handling,
+// it is intended to be served at MetadataPath
+// https://tools.ietf.org/html/rfc8414
+func (s *Server) HandleMetadataRequest(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != "GET" {
+		return s.tokenError(w, errors.ErrInvalidRequest)
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(s.GetMetadata())
+}
@@ -0,0 +1,46 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/server"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMetadata(t *testing.T) {
+	Convey("Test authorization server metadata", t, func() {
+		cfg := server.NewConfig()
+		cfg.Issuer = "https://as.example.com"
+		cfg.AuthorizeEndpoint = "https://as.example.com/authorize"
+		cfg.TokenEndpoint = "https://as.example.com/token"
+		cfg.AllowedGrantTypes = []oauth2.GrantType{oauth2.AuthorizationCode, oauth2.Refreshing}
+		cfg.AllowedCodeChallengeMethods = []oauth2.CodeChallengeMethod{oauth2.CodeChallengeS256}
+		srv := server.NewServer(cfg, manager)
+
+		w := httptest.NewRecorder()
+		err := srv.HandleMetadataRequest(w, httptest.NewRequest(http.MethodGet, server.MetadataPath, nil))
+		So(err, ShouldBeNil)
+		So(w.Code, ShouldEqual, http.StatusOK)
+
+		var data map[string]interface{}
+		So(json.Unmarshal(w.Body.Bytes(), &data), ShouldBeNil)
+		So(data["issuer"], ShouldEqual, "https://as.example.com")
+		So(data["authorization_endpoint"], ShouldEqual, "https://as.example.com/authorize")
+		So(data["token_endpoint"], ShouldEqual, "https://as.example.com/token")
+		So(data, ShouldNotContainKey, "revocation_endpoint")
+		So(data["response_types_supported"], ShouldResemble, []interface{}{"code", "token"})
+		So(data["grant_types_supported"], ShouldResemble, []interface{}{"authorization_code", "refresh_token", "implicit"})
+		So(data["code_challenge_methods_supported"], ShouldResemble, []interface{}{"S256"})
+		So(data["token_endpoint_auth_methods_supported"], ShouldResemble, []interface{}{"client_secret_basic"})
+
+		Convey("the metadata without the issuer", func() {
+			cfg.Issuer = ""
+			So(srv.GetMetadata(), ShouldNotContainKey, "issuer")
+		})
+	})
+}
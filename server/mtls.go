@@ -0,0 +1,145 @@
+package server
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// get the client certificate of the mutual TLS connection, or of the trusted header
+// when the TLS is terminated upstream, verified reports the certificate chain is validated
+func (s *Server) clientCertificate(r *http.Request) (cert *x509.Certificate, verified bool, err error) {
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		return r.TLS.PeerCertificates[0], len(r.TLS.VerifiedChains) > 0, nil
+	}
+
+	// the header is ignored unless the request comes from a trusted proxy
+	name := s.Config.TLSClientCertificateHeader
+	if name == "" || r.Header.Get(name) == "" || !s.trustedProxy(r) {
+		return nil, false, nil
+	}
+
+	// the proxy forwards the validated certificate as url encoded PEM
+	v, err := url.QueryUnescape(r.Header.Get(name))
+	if err != nil {
+		return nil, false, errors.ErrInvalidRequest
+	}
+	block, _ := pem.Decode([]byte(v))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, false, errors.ErrInvalidRequest
+	}
+	cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, false, errors.ErrInvalidRequest
+	}
+	return cert, true, nil
+}
+
+// check the request comes from one of the trusted proxies
+func (s *Server) trustedProxy(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, v := range s.Config.TrustedProxies {
+		_, ipnet, err := net.ParseCIDR(v)
+		if err == nil && ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// the base64url encoded SHA-256 thumbprint of the certificate (RFC 8705 section 3.1)
+func certificateThumbprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// check the certificate-bound access token is presented over the mutual TLS connection
+// with the same certificate
+func (s *Server) validCertificateBinding(r *http.Request, cnf *oauth2.Confirmation) error {
+	if cnf == nil || cnf.X5TS256 == "" {
+		return nil
+	}
+
+	cert, _, err := s.clientCertificate(r)
+	if err != nil || cert == nil || certificateThumbprint(cert) != cnf.X5TS256 {
+		return errors.ErrInvalidAccessToken
+	}
+	return nil
+}
+
+// ClientTLSHandler get client data by the PKI mutual TLS client authentication (RFC 8705 section 2.1),
+// the certificate chain must be validated by the TLS server and its subject must match the client
+func (s *Server) ClientTLSHandler(r *http.Request) (string, string, error) {
+	clientID := r.FormValue("client_id")
+	if clientID == "" {
+		return "", "", errors.ErrInvalidClient
+	}
+
+	cert, verified, err := s.clientCertificate(r)
+	if err != nil || cert == nil || !verified {
+		return "", "", errors.ErrInvalidClient
+	}
+
+	cli, err := s.Manager.GetClient(r.Context(), clientID)
+	if err != nil {
+		return "", "", errors.ErrInvalidClient
+	}
+	tcli, ok := cli.(oauth2.TLSClientInfo)
+	if !ok || tcli.GetTLSClientAuthSubjectDN() == "" ||
+		tcli.GetTLSClientAuthSubjectDN() != cert.Subject.String() {
+		return "", "", errors.ErrInvalidClient
+	}
+
+	setClientAuthMethod(r, oauth2.TLSClientAuth)
+	return clientID, "", nil
+}
+
+// ClientSelfSignedTLSHandler get client data by the self-signed certificate mutual TLS client authentication
+// (RFC 8705 section 2.2), the public key of the certificate must be one of the registered keys of the client
+func (s *Server) ClientSelfSignedTLSHandler(r *http.Request) (string, string, error) {
+	clientID := r.FormValue("client_id")
+	if clientID == "" {
+		return "", "", errors.ErrInvalidClient
+	}
+
+	cert, _, err := s.clientCertificate(r)
+	if err != nil || cert == nil {
+		return "", "", errors.ErrInvalidClient
+	}
+	if now := time.Now(); now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return "", "", errors.ErrInvalidClient
+	}
+
+	keys, err := s.clientKeys(r.Context(), clientID)
+	if err != nil || keys == nil {
+		return "", "", errors.ErrInvalidClient
+	}
+	for _, key := range keys.Keys {
+		pub, err := key.PublicKey()
+		if err != nil {
+			continue
+		}
+		if epub, ok := pub.(interface{ Equal(crypto.PublicKey) bool }); ok && epub.Equal(cert.PublicKey) {
+			setClientAuthMethod(r, oauth2.SelfSignedTLSClientAuth)
+			return clientID, "", nil
+		}
+	}
+	return "", "", errors.ErrInvalidClient
+}
@@ -0,0 +1,292 @@
+package server_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+)
+
+// generate a certificate of the subject, signed by the parent or self-signed without it
+func testCertificate(t *testing.T, subject pkix.Name, isCA bool, parent *tls.Certificate) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               subject,
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	issuer, signer := tpl, interface{}(key)
+	if parent != nil {
+		issuer, signer = parent.Leaf, parent.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tpl, issuer, key.Public(), signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func certThumbprint(cert tls.Certificate) string {
+	sum := sha256.Sum256(cert.Leaf.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// start the TLS test server requesting the client certificates
+func testTLSServer(clientAuth tls.ClientAuthType, ca *tls.Certificate) *httptest.Server {
+	tsrv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.Path {
+		case "/token":
+			err = srv.HandleTokenRequest(w, r)
+		case "/introspect":
+			err = srv.HandleIntrospectionRequest(w, r)
+		case "/resource":
+			_, err = srv.ValidationBearerToken(r)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		}
+	}))
+	tsrv.TLS = &tls.Config{ClientAuth: clientAuth}
+	if ca != nil {
+		tsrv.TLS.ClientCAs = x509.NewCertPool()
+		tsrv.TLS.ClientCAs.AddCert(ca.Leaf)
+	}
+	tsrv.StartTLS()
+	return tsrv
+}
+
+// the expect instance of the client presenting the certificates
+func tlsExpect(t *testing.T, tsrv *httptest.Server, certs ...tls.Certificate) *httpexpect.Expect {
+	transport := tsrv.Client().Transport.(*http.Transport).Clone()
+	transport.TLSClientConfig.Certificates = certs
+	return httpexpect.WithConfig(httpexpect.Config{
+		BaseURL:  tsrv.URL,
+		Client:   &http.Client{Transport: transport},
+		Reporter: httpexpect.NewAssertReporter(t),
+	})
+}
+
+func TestClientTLSHandler(t *testing.T) {
+	ca := testCertificate(t, pkix.Name{CommonName: "ca"}, true, nil)
+	cert := testCertificate(t, pkix.Name{CommonName: "mtls-client", Organization: []string{"Example"}}, false, &ca)
+	other := testCertificate(t, pkix.Name{CommonName: "other", Organization: []string{"Example"}}, false, &ca)
+
+	tsrv := testTLSServer(tls.VerifyClientCertIfGiven, &ca)
+	defer tsrv.Close()
+
+	clientStore := store.NewClientStore()
+	clientStore.Set(clientID, &models.Client{
+		ID:           clientID,
+		TLSSubjectDN: "CN=mtls-client,O=Example",
+	})
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	mgr.MapClientStorage(clientStore)
+
+	srv = server.NewDefaultServer(mgr)
+	srv.SetClientInfoHandler(srv.ClientTLSHandler)
+
+	e := tlsExpect(t, tsrv, cert)
+	access := e.POST("/token").
+		WithFormField("grant_type", "client_credentials").
+		WithFormField("client_id", clientID).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("access_token").String().Raw()
+
+	e.POST("/introspect").
+		WithFormField("client_id", clientID).
+		WithFormField("token", access).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("cnf").Object().Value("x5t#S256").Equal(certThumbprint(cert))
+
+	e.GET("/resource").
+		WithHeader("Authorization", "Bearer "+access).
+		Expect().
+		Status(http.StatusOK)
+
+	// the token presented over a different certificate
+	oe := tlsExpect(t, tsrv, other)
+	oe.GET("/resource").
+		WithHeader("Authorization", "Bearer "+access).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	// the certificate of a different subject
+	oe.POST("/token").
+		WithFormField("grant_type", "client_credentials").
+		WithFormField("client_id", clientID).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	// the certificate isn't issued by a trusted authority
+	self := testCertificate(t, pkix.Name{CommonName: "mtls-client", Organization: []string{"Example"}}, false, nil)
+	tsrv2 := testTLSServer(tls.RequestClientCert, nil)
+	defer tsrv2.Close()
+	tlsExpect(t, tsrv2, self).POST("/token").
+		WithFormField("grant_type", "client_credentials").
+		WithFormField("client_id", clientID).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
+
+func TestClientSelfSignedTLSHandler(t *testing.T) {
+	cert := testCertificate(t, pkix.Name{CommonName: "self-signed"}, false, nil)
+	other := testCertificate(t, pkix.Name{CommonName: "self-signed"}, false, nil)
+
+	tsrv := testTLSServer(tls.RequestClientCert, nil)
+	defer tsrv.Close()
+
+	jwk, err := models.NewJWK("", "ES256", cert.Leaf.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientStore := store.NewClientStore()
+	clientStore.Set(clientID, &models.Client{
+		ID:     clientID,
+		Secret: clientSecret,
+		JWKS:   &models.JWKSet{Keys: []*models.JWK{jwk}},
+	})
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	mgr.MapClientStorage(clientStore)
+
+	srv = server.NewDefaultServer(mgr)
+	srv.SetClientInfoHandler(srv.ClientSelfSignedTLSHandler)
+
+	e := tlsExpect(t, tsrv, cert)
+	access := e.POST("/token").
+		WithFormField("grant_type", "client_credentials").
+		WithFormField("client_id", clientID).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("access_token").String().Raw()
+
+	e.GET("/resource").
+		WithHeader("Authorization", "Bearer "+access).
+		Expect().
+		Status(http.StatusOK)
+
+	// the token presented without the certificate
+	tlsExpect(t, tsrv).GET("/resource").
+		WithHeader("Authorization", "Bearer "+access).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	// the certificate of a key that isn't registered
+	tlsExpect(t, tsrv, other).POST("/token").
+		WithFormField("grant_type", "client_credentials").
+		WithFormField("client_id", clientID).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
+
+func TestTLSClientCertificateHeader(t *testing.T) {
+	ca := testCertificate(t, pkix.Name{CommonName: "ca"}, true, nil)
+	cert := testCertificate(t, pkix.Name{CommonName: "mtls-client"}, false, &ca)
+
+	psrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.Path {
+		case "/token":
+			err = srv.HandleTokenRequest(w, r)
+		case "/resource":
+			_, err = srv.ValidationBearerToken(r)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		}
+	}))
+	defer psrv.Close()
+	e := httpexpect.New(t, psrv.URL)
+
+	clientStore := store.NewClientStore()
+	clientStore.Set(clientID, &models.Client{
+		ID:           clientID,
+		TLSSubjectDN: "CN=mtls-client",
+	})
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	mgr.MapClientStorage(clientStore)
+
+	srv = server.NewDefaultServer(mgr)
+	srv.Config.TLSClientCertificateHeader = "X-Client-Cert"
+	srv.SetClientInfoHandler(srv.ClientTLSHandler)
+
+	header := url.QueryEscape(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Leaf.Raw})))
+
+	// the header of the request that doesn't come from a trusted proxy is ignored
+	srv.Config.TrustedProxies = []string{"10.0.0.0/8"}
+	e.POST("/token").
+		WithHeader("X-Client-Cert", header).
+		WithFormField("grant_type", "client_credentials").
+		WithFormField("client_id", clientID).
+		Expect().
+		Status(http.StatusUnauthorized)
+	srv.Config.TrustedProxies = []string{"127.0.0.1/32", "::1/128"}
+
+	e.POST("/token").
+		WithFormField("grant_type", "client_credentials").
+		WithFormField("client_id", clientID).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	access := e.POST("/token").
+		WithHeader("X-Client-Cert", header).
+		WithFormField("grant_type", "client_credentials").
+		WithFormField("client_id", clientID).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("access_token").String().Raw()
+
+	e.GET("/resource").
+		WithHeader("X-Client-Cert", header).
+		WithHeader("Authorization", "Bearer "+access).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/resource").
+		WithHeader("Authorization", "Bearer "+access).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
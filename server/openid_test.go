@@ -0,0 +1,94 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect"
+	"github.com/go-oauth2/oauth2/v4/generates"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestOpenIDAuthorizeCode(t *testing.T) {
+	tsrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testServer(t, w, r)
+	}))
+	defer tsrv.Close()
+
+	e := httpexpect.New(t, tsrv.URL)
+
+	csrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth2" {
+			return
+		}
+		resObj := e.POST("/token").
+			WithFormField("redirect_uri", csrv.URL+"/oauth2").
+			WithFormField("code", r.FormValue("code")).
+			WithFormField("grant_type", "authorization_code").
+			WithBasicAuth(clientID, clientSecret).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object()
+
+		claims := jwt.MapClaims{}
+		_, err := jwt.ParseWithClaims(resObj.Value("id_token").String().Raw(), claims, func(t *jwt.Token) (interface{}, error) {
+			return []byte("00000000"), nil
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if claims["nonce"] != "n-0S6_WzA2Mj" || claims["sub"] != "000000" ||
+			claims["aud"] != clientID || claims["iss"] != "https://as.example.com" {
+			t.Errorf("unexpected id token claims: %v", claims)
+		}
+		if claims["auth_time"] == nil || claims["at_hash"] == nil || claims["c_hash"] == nil {
+			t.Errorf("missing id token claims: %v", claims)
+		}
+
+		// the id token reissued on refresh carries no nonce
+		resObj = e.POST("/token").
+			WithFormField("refresh_token", resObj.Value("refresh_token").String().Raw()).
+			WithFormField("grant_type", "refresh_token").
+			WithBasicAuth(clientID, clientSecret).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object()
+
+		claims = jwt.MapClaims{}
+		_, err = jwt.ParseWithClaims(resObj.Value("id_token").String().Raw(), claims, func(t *jwt.Token) (interface{}, error) {
+			return []byte("00000000"), nil
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if _, ok := claims["nonce"]; ok || claims["sub"] != "000000" || claims["auth_time"] == nil {
+			t.Errorf("unexpected refreshed id token claims: %v", claims)
+		}
+	}))
+	defer csrv.Close()
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	mgr.MapClientStorage(clientStore(csrv.URL+"/oauth2", false))
+	mgr.MapIDTokenGenerate(generates.NewJWTIDTokenGenerate("https://as.example.com",
+		generates.NewJWTAccessGenerate("", []byte("00000000"), jwt.SigningMethodHS256)))
+
+	srv = server.NewDefaultServer(mgr)
+	srv.SetUserAuthorizationHandler(func(w http.ResponseWriter, r *http.Request) (userID string, err error) {
+		return "000000", nil
+	})
+
+	e.GET("/authorize").
+		WithQuery("response_type", "code").
+		WithQuery("client_id", clientID).
+		WithQuery("scope", "openid profile").
+		WithQuery("nonce", "n-0S6_WzA2Mj").
+		WithQuery("redirect_uri", csrv.URL+"/oauth2").
+		Expect().Status(http.StatusOK)
+}
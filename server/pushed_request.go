@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// the client authentication parameters are never stored with the pushed authorization request
+var clientAuthParams = []string{"client_secret", "client_assertion", "client_assertion_type"}
+
+// check the authorization requests of the client must be pushed
+func (s *Server) pushedRequestRequired(ctx context.Context, clientID string) (bool, error) {
+	if s.Config.RequirePushedAuthorize {
+		return true, nil
+	}
+
+	cli, err := s.Manager.GetClient(ctx, clientID)
+	if err != nil {
+		return false, err
+	}
+	if pcli, ok := cli.(oauth2.PushedRequestClientInfo); ok {
+		return pcli.IsPushedRequestRequired(), nil
+	}
+	return false, nil
+}
+
+// replace the parameters of the authorization request with the pushed authorization request
+// referenced by the request uri, and return the request uri
+func (s *Server) resolvePushedRequest(r *http.Request) (string, error) {
+	// the other request uri references a request object
+	requestURI := r.FormValue("request_uri")
+	if !strings.HasPrefix(requestURI, oauth2.RequestURIPrefix) {
+		return "", nil
+	}
+
+	clientID := r.FormValue("client_id")
+	if clientID == "" {
+		return "", errors.ErrInvalidRequest
+	}
+
+	pm, ok := s.Manager.(oauth2.PushedRequestManager)
+	if !ok {
+		return "", errors.ErrInvalidRequestURI
+	}
+	pi, err := pm.LoadPushedRequest(r.Context(), clientID, requestURI)
+	if err != nil {
+		if err == errors.ErrInvalidPushedRequest {
+			return "", errors.ErrInvalidRequestURI
+		}
+		return "", err
+	}
+
+	// the parameters outside the pushed authorization request are ignored
+	params := make(url.Values)
+	for k, v := range pi.GetParams() {
+		params[k] = v
+	}
+	params.Set("client_id", clientID)
+	params.Set("request_uri", requestURI)
+	r.Form = params
+
+	return requestURI, nil
+}
+
+// HandlePushedAuthorizationRequest the pushed authorization request handling
+// https://tools.ietf.org/html/rfc9126#section-2
+func (s *Server) HandlePushedAuthorizationRequest(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	if r.Method != "POST" {
+		return s.tokenError(w, errors.ErrInvalidRequest)
+	}
+
+	cli, err := s.authenticateClient(r)
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+
+	if r.Form.Get("request_uri") != "" {
+		return s.tokenError(w, errors.ErrInvalidRequest)
+	} else if id := r.Form.Get("client_id"); id != "" && id != cli.GetID() {
+		return s.tokenError(w, errors.ErrInvalidRequest)
+	}
+	r.Form.Set("client_id", cli.GetID())
+
+	if _, err := s.ValidationAuthorizeRequest(r); err != nil {
+		return s.tokenError(w, err)
+	}
+
+	pm, ok := s.Manager.(oauth2.PushedRequestManager)
+	if !ok {
+		return s.tokenError(w, errors.ErrInvalidRequest)
+	}
+
+	params := make(url.Values)
+	for k, v := range r.Form {
+		params[k] = v
+	}
+	for _, k := range clientAuthParams {
+		params.Del(k)
+	}
+
+	pi, err := pm.PushAuthorizeRequest(ctx, cli.GetID(), params)
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+
+	data := map[string]interface{}{
+		"request_uri": pi.GetRequestURI(),
+		"expires_in":  int64(pi.GetExpiresIn().Seconds()),
+	}
+	return s.token(w, data, nil, http.StatusCreated)
+}
@@ -0,0 +1,124 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+)
+
+func TestPushedAuthorizationRequest(t *testing.T) {
+	psrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.Path {
+		case "/par":
+			err = srv.HandlePushedAuthorizationRequest(w, r)
+		case "/authorize":
+			err = srv.HandleAuthorizeRequest(w, r)
+		case "/token":
+			err = srv.HandleTokenRequest(w, r)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}))
+	defer psrv.Close()
+	e := httpexpect.New(t, psrv.URL)
+
+	codes := make(chan string, 1)
+	csrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth2" && r.FormValue("state") == "123" {
+			codes <- r.FormValue("code")
+		}
+	}))
+	defer csrv.Close()
+
+	cs := store.NewClientStore()
+	cs.Set(clientID, &models.Client{ID: clientID, Secret: clientSecret, Domain: csrv.URL + "/oauth2", RequirePushedRequest: true})
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	mgr.MustPushedRequestStorage(store.NewMemoryPushedRequestStore())
+	mgr.MapClientStorage(cs)
+
+	srv = server.NewDefaultServer(mgr)
+	srv.SetUserAuthorizationHandler(func(w http.ResponseWriter, r *http.Request) (string, error) {
+		return "000000", nil
+	})
+
+	// the client must push the authorization request
+	e.GET("/authorize").
+		WithQuery("response_type", "code").
+		WithQuery("client_id", clientID).
+		WithQuery("redirect_uri", csrv.URL+"/oauth2").
+		Expect().
+		Status(http.StatusBadRequest)
+
+	e.POST("/par").
+		WithFormField("response_type", "code").
+		WithFormField("redirect_uri", csrv.URL+"/oauth2").
+		WithFormField("request_uri", oauth2.RequestURIPrefix+"x").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Equal("invalid_request")
+
+	resObj := e.POST("/par").
+		WithFormField("response_type", "code").
+		WithFormField("redirect_uri", csrv.URL+"/oauth2").
+		WithFormField("scope", "all").
+		WithFormField("state", "123").
+		WithFormField("code_challenge", s256ChallengeHash).
+		WithFormField("code_challenge_method", "S256").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object()
+	resObj.Value("expires_in").Equal(60)
+	requestURI := resObj.Value("request_uri").String().Raw()
+
+	// the parameters outside the pushed authorization request are ignored
+	e.GET("/authorize").
+		WithQuery("client_id", clientID).
+		WithQuery("request_uri", requestURI).
+		WithQuery("scope", "admin").
+		Expect().
+		Status(http.StatusOK)
+	code := receiveCode(t, codes)
+
+	resObj = e.POST("/token").
+		WithFormField("grant_type", "authorization_code").
+		WithFormField("redirect_uri", csrv.URL+"/oauth2").
+		WithFormField("code", code).
+		WithFormField("code_verifier", s256Challenge).
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	resObj.Value("scope").Equal("all")
+
+	// the request uri is used only once
+	e.GET("/authorize").
+		WithQuery("client_id", clientID).
+		WithQuery("request_uri", requestURI).
+		Expect().
+		Status(http.StatusBadRequest)
+}
+
+// receive the authorization code redirected to the client
+func receiveCode(t *testing.T, codes chan string) string {
+	select {
+	case code := <-codes:
+		return code
+	case <-time.After(time.Second * 5):
+		t.Fatal("the authorization code is not received")
+	}
+	return ""
+}
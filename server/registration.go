@@ -0,0 +1,368 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/google/uuid"
+)
+
+// the client authentication methods the client secret is issued for
+var secretAuthMethods = map[oauth2.ClientAuthMethod]bool{
+	oauth2.ClientSecretBasic: true,
+	oauth2.ClientSecretPost:  true,
+	oauth2.ClientSecretJWT:   true,
+}
+
+// the update request of the client configuration, the client identifies itself
+// with the client id and the current secret
+type clientUpdateRequest struct {
+	models.ClientMetadata
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// generate the random client secret or registration access token
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// check the token endpoint accepts the client authentication method
+func (s *Server) checkAuthMethod(method oauth2.ClientAuthMethod) bool {
+	if method == oauth2.ClientAuthNone {
+		return true
+	}
+	for _, m := range s.Config.TokenEndpointAuthMethods {
+		if m == method.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// check the redirect uri can be registered, the plain http is only allowed for the loopback interface
+// and the private-use scheme must be a reverse domain name (RFC 8252 section 7)
+func validRedirectURI(v string) bool {
+	u, err := url.Parse(v)
+	if err != nil || !u.IsAbs() || u.Fragment != "" {
+		return false
+	}
+
+	switch u.Scheme {
+	case "https":
+		return u.Host != ""
+	case "http":
+		host := u.Hostname()
+		if host == "localhost" {
+			return true
+		}
+		ip := net.ParseIP(host)
+		return ip != nil && ip.IsLoopback()
+	}
+	return strings.Contains(u.Scheme, ".")
+}
+
+// validate the metadata of the client and fill in the default values
+// https://tools.ietf.org/html/rfc7591#section-2
+func (s *Server) validClientMetadata(md *models.ClientMetadata) error {
+	if md.TokenEndpointAuthMethod == "" {
+		md.TokenEndpointAuthMethod = oauth2.ClientSecretBasic.String()
+	}
+	if len(md.GrantTypes) == 0 {
+		md.GrantTypes = []string{oauth2.AuthorizationCode.String()}
+	}
+
+	grants := make(map[string]bool)
+	for _, gt := range md.GrantTypes {
+		if gt == "implicit" {
+			if !s.CheckResponseType(oauth2.Token) {
+				return errors.ErrInvalidClientMetadata
+			}
+		} else if !s.CheckGrantType(oauth2.GrantType(gt)) {
+			return errors.ErrInvalidClientMetadata
+		}
+		grants[gt] = true
+	}
+
+	// the response types must be consistent with the grant types
+	if len(md.ResponseTypes) == 0 {
+		if grants[oauth2.AuthorizationCode.String()] {
+			md.ResponseTypes = append(md.ResponseTypes, oauth2.Code.String())
+		}
+		if grants["implicit"] {
+			md.ResponseTypes = append(md.ResponseTypes, oauth2.Token.String())
+		}
+	}
+	for _, rt := range md.ResponseTypes {
+		switch oauth2.ResponseType(rt) {
+		case oauth2.Code:
+			if !grants[oauth2.AuthorizationCode.String()] {
+				return errors.ErrInvalidClientMetadata
+			}
+		case oauth2.Token:
+			if !grants["implicit"] {
+				return errors.ErrInvalidClientMetadata
+			}
+		default:
+			return errors.ErrInvalidClientMetadata
+		}
+		if !s.CheckResponseType(oauth2.ResponseType(rt)) {
+			return errors.ErrInvalidClientMetadata
+		}
+	}
+
+	if len(md.ResponseTypes) > 0 && len(md.RedirectURIs) == 0 {
+		return errors.ErrInvalidClientRedirectURI
+	}
+	for _, v := range md.RedirectURIs {
+		if !validRedirectURI(v) {
+			return errors.ErrInvalidClientRedirectURI
+		}
+	}
+
+	method := oauth2.ClientAuthMethod(md.TokenEndpointAuthMethod)
+	if !s.checkAuthMethod(method) {
+		return errors.ErrInvalidClientMetadata
+	} else if md.JWKS != nil && md.JWKSURI != "" {
+		return errors.ErrInvalidClientMetadata
+	}
+
+	switch method {
+	case oauth2.PrivateKeyJWT, oauth2.SelfSignedTLSClientAuth:
+		// the keys of the jwks uri aren't fetched
+		if md.JWKS == nil || len(md.JWKS.Keys) == 0 {
+			return errors.ErrInvalidClientMetadata
+		}
+	case oauth2.TLSClientAuth:
+		if md.TLSClientAuthSubjectDN == "" {
+			return errors.ErrInvalidClientMetadata
+		}
+	}
+	return nil
+}
+
+// create the client information of the registered metadata,
+// only the hash of the registration access token is kept
+func newRegisteredClient(clientID, secret, token string, md *models.ClientMetadata) *models.Client {
+	cli := &models.Client{
+		ID:                      clientID,
+		Secret:                  secret,
+		Public:                  md.TokenEndpointAuthMethod == oauth2.ClientAuthNone.String(),
+		RedirectURIs:            md.RedirectURIs,
+		Scope:                   md.Scope,
+		TokenEndpointAuthMethod: oauth2.ClientAuthMethod(md.TokenEndpointAuthMethod),
+		RequirePushedRequest:    md.RequirePushedAuthorizationRequests,
+		JWKS:                    md.JWKS,
+		TLSSubjectDN:            md.TLSClientAuthSubjectDN,
+		Metadata:                md,
+		RegistrationToken:       models.HashRegistrationToken(token),
+	}
+	if len(md.RedirectURIs) > 0 {
+		cli.Domain = md.RedirectURIs[0]
+	}
+	for _, gt := range md.GrantTypes {
+		cli.GrantTypes = append(cli.GrantTypes, oauth2.GrantType(gt))
+	}
+	for _, rt := range md.ResponseTypes {
+		cli.ResponseTypes = append(cli.ResponseTypes, oauth2.ResponseType(rt))
+	}
+	return cli
+}
+
+// issue the secret of the client when its authentication method uses one,
+// the current secret is kept
+func issueSecret(md *models.ClientMetadata, current string) (string, error) {
+	if !secretAuthMethods[oauth2.ClientAuthMethod(md.TokenEndpointAuthMethod)] {
+		return "", nil
+	} else if current != "" {
+		return current, nil
+	}
+	return randomToken()
+}
+
+// the URL of the client configuration endpoint of the client
+func (s *Server) registrationClientURI(clientID string) string {
+	return strings.TrimSuffix(s.Config.RegistrationEndpoint, "/") + "/" + url.PathEscape(clientID)
+}
+
+// GetRegistrationData the client information response of the registered client
+func (s *Server) GetRegistrationData(cli oauth2.ClientInfo, md *models.ClientMetadata, token string) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	if md != nil {
+		buf, err := json.Marshal(md)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(buf, &data); err != nil {
+			return nil, err
+		}
+	}
+
+	data["client_id"] = cli.GetID()
+	if secret := cli.GetSecret(); secret != "" {
+		data["client_secret"] = secret
+		data["client_secret_expires_at"] = 0
+	}
+	if token != "" {
+		data["registration_access_token"] = token
+	}
+	data["registration_client_uri"] = s.registrationClientURI(cli.GetID())
+	return data, nil
+}
+
+// HandleRegistrationRequest the dynamic client registration request handling
+// https://tools.ietf.org/html/rfc7591#section-3
+func (s *Server) HandleRegistrationRequest(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	if r.Method != "POST" {
+		return s.tokenError(w, errors.ErrInvalidRequest)
+	}
+
+	if fn := s.RegistrationAccessHandler; fn != nil {
+		allowed, err := fn(r)
+		if err != nil {
+			return s.tokenError(w, err)
+		} else if !allowed {
+			return s.tokenError(w, errors.ErrAccessDenied)
+		}
+	}
+
+	rm, ok := s.Manager.(oauth2.ClientRegistrationManager)
+	if !ok {
+		return s.tokenError(w, errors.ErrServerError)
+	}
+
+	md := &models.ClientMetadata{}
+	if err := json.NewDecoder(r.Body).Decode(md); err != nil {
+		return s.tokenError(w, errors.ErrInvalidClientMetadata)
+	}
+	if err := s.validClientMetadata(md); err != nil {
+		return s.tokenError(w, err)
+	}
+
+	secret, err := issueSecret(md, "")
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+	token, err := randomToken()
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+
+	cli := newRegisteredClient(uuid.Must(uuid.NewRandom()).String(), secret, token, md)
+	if err := rm.CreateClient(ctx, cli); err != nil {
+		return s.tokenError(w, err)
+	}
+
+	data, err := s.GetRegistrationData(cli, md, token)
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+	data["client_id_issued_at"] = time.Now().Unix()
+	return s.token(w, data, nil, http.StatusCreated)
+}
+
+// get the registered client identified by the registration client uri of the request,
+// the request must present the registration access token of the client
+func (s *Server) loadRegisteredClient(r *http.Request) (oauth2.ClientInfo, models.RegisteredClient, bool) {
+	auth := r.Header.Get("Authorization")
+	prefix := "Bearer "
+	if !strings.HasPrefix(auth, prefix) {
+		return nil, nil, false
+	}
+
+	cli, err := s.Manager.GetClient(r.Context(), path.Base(r.URL.Path))
+	if err != nil || cli == nil {
+		return nil, nil, false
+	}
+	rc, ok := cli.(models.RegisteredClient)
+	if !ok || !rc.VerifyRegistrationToken(auth[len(prefix):]) {
+		return nil, nil, false
+	}
+	return cli, rc, true
+}
+
+// HandleClientConfigurationRequest the configuration request handling of the registered client,
+// the client id is the last path segment of the registration client uri
+// https://tools.ietf.org/html/rfc7592#section-2
+func (s *Server) HandleClientConfigurationRequest(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	rm, ok := s.Manager.(oauth2.ClientRegistrationManager)
+	if !ok {
+		return s.tokenError(w, errors.ErrServerError)
+	}
+
+	cli, rc, ok := s.loadRegisteredClient(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return nil
+	}
+
+	switch r.Method {
+	case "GET":
+		data, err := s.GetRegistrationData(cli, rc.GetMetadata(), "")
+		if err != nil {
+			return s.tokenError(w, err)
+		}
+		return s.token(w, data, nil)
+	case "PUT":
+		req := &clientUpdateRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			return s.tokenError(w, errors.ErrInvalidClientMetadata)
+		} else if req.ClientID != cli.GetID() ||
+			(req.ClientSecret != "" && req.ClientSecret != cli.GetSecret()) {
+			return s.tokenError(w, errors.ErrInvalidRequest)
+		}
+
+		md := &req.ClientMetadata
+		if err := s.validClientMetadata(md); err != nil {
+			return s.tokenError(w, err)
+		}
+
+		secret, err := issueSecret(md, cli.GetSecret())
+		if err != nil {
+			return s.tokenError(w, err)
+		}
+		// the registration access token is rotated with each update
+		token, err := randomToken()
+		if err != nil {
+			return s.tokenError(w, err)
+		}
+
+		ncli := newRegisteredClient(cli.GetID(), secret, token, md)
+		ncli.UserID = cli.GetUserID()
+		if err := rm.UpdateClient(ctx, ncli); err != nil {
+			return s.tokenError(w, err)
+		}
+
+		data, err := s.GetRegistrationData(ncli, md, token)
+		if err != nil {
+			return s.tokenError(w, err)
+		}
+		return s.token(w, data, nil)
+	case "DELETE":
+		if err := rm.RemoveClient(ctx, cli.GetID()); err != nil {
+			return s.tokenError(w, err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	return s.tokenError(w, errors.ErrInvalidRequest)
+}
@@ -0,0 +1,133 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gavv/httpexpect"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+)
+
+func TestClientRegistration(t *testing.T) {
+	rsrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch {
+		case r.URL.Path == "/register":
+			err = srv.HandleRegistrationRequest(w, r)
+		case strings.HasPrefix(r.URL.Path, "/register/"):
+			err = srv.HandleClientConfigurationRequest(w, r)
+		case r.URL.Path == "/token":
+			err = srv.HandleTokenRequest(w, r)
+		}
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer rsrv.Close()
+	e := httpexpect.New(t, rsrv.URL)
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	mgr.MapClientStorage(store.NewClientStore())
+
+	cfg := server.NewConfig()
+	cfg.RegistrationEndpoint = rsrv.URL + "/register"
+	srv = server.NewServer(cfg, mgr)
+
+	e.POST("/register").
+		WithJSON(map[string]interface{}{
+			"redirect_uris": []string{"https://client.example.com/cb#fragment"},
+		}).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Equal("invalid_redirect_uri")
+
+	e.POST("/register").
+		WithJSON(map[string]interface{}{
+			"redirect_uris": []string{"http://client.example.com/cb"},
+		}).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Equal("invalid_redirect_uri")
+
+	e.POST("/register").
+		WithJSON(map[string]interface{}{
+			"redirect_uris":              []string{"https://client.example.com/cb"},
+			"token_endpoint_auth_method": "private_key_jwt",
+		}).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Equal("invalid_client_metadata")
+
+	resObj := e.POST("/register").
+		WithJSON(map[string]interface{}{
+			"redirect_uris": []string{"https://client.example.com/cb"},
+			"grant_types":   []string{"authorization_code", "client_credentials"},
+			"client_name":   "Example",
+			"contacts":      []string{"ops@client.example.com"},
+			"scope":         "read write",
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object()
+	resObj.Value("response_types").Array().Elements("code")
+	resObj.Value("token_endpoint_auth_method").Equal("client_secret_basic")
+	resObj.Value("client_name").Equal("Example")
+	id := resObj.Value("client_id").String().NotEmpty().Raw()
+	secret := resObj.Value("client_secret").String().NotEmpty().Raw()
+	token := resObj.Value("registration_access_token").String().NotEmpty().Raw()
+	resObj.Value("registration_client_uri").Equal(rsrv.URL + "/register/" + id)
+
+	// the registered client is authenticated by the issued secret
+	e.POST("/token").
+		WithFormField("grant_type", "client_credentials").
+		WithBasicAuth(id, secret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("access_token").String().NotEmpty()
+
+	e.GET("/register/"+id).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("contacts").Array().Elements("ops@client.example.com")
+
+	e.GET("/register/"+id).
+		WithHeader("Authorization", "Bearer invalid").
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	resObj = e.PUT("/register/"+id).
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"client_id":     id,
+			"redirect_uris": []string{"https://client.example.com/callback"},
+			"client_name":   "Renamed",
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	resObj.Value("client_name").Equal("Renamed")
+	resObj.Value("client_secret").Equal(secret)
+	newToken := resObj.Value("registration_access_token").String().NotEqual(token).Raw()
+
+	// the registration access token is rotated
+	e.GET("/register/"+id).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	e.DELETE("/register/"+id).
+		WithHeader("Authorization", "Bearer "+newToken).
+		Expect().
+		Status(http.StatusNoContent)
+
+	e.GET("/register/"+id).
+		WithHeader("Authorization", "Bearer "+newToken).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// the registered claims of the request object are not authorization request parameters
+var requestObjectClaims = map[string]bool{
+	"iss": true, "aud": true, "exp": true, "iat": true, "nbf": true, "jti": true, "sub": true,
+}
+
+// get the public keys of the client
+func (s *Server) clientKeys(ctx context.Context, clientID string) (*models.JWKSet, error) {
+	if fn := s.ClientKeysHandler; fn != nil {
+		return fn(ctx, clientID)
+	}
+
+	cli, err := s.Manager.GetClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if kcli, ok := cli.(models.KeySetClient); ok {
+		return kcli.GetJWKSet(), nil
+	}
+	return nil, nil
+}
+
+// format the claim value of the request object as authorization request parameter
+func requestObjectParam(v interface{}) (string, error) {
+	switch tv := v.(type) {
+	case string:
+		return tv, nil
+	case float64, bool:
+		return fmt.Sprint(tv), nil
+	}
+	jv, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(jv), nil
+}
+
+// verify the request object signed by the client,
+// and override the parameters of the authorization request with its claims
+// https://tools.ietf.org/html/rfc9101#section-6
+func (s *Server) resolveRequestObject(r *http.Request) error {
+	ctx := r.Context()
+
+	requestObject := r.FormValue("request")
+	if uri := r.FormValue("request_uri"); uri != "" && !strings.HasPrefix(uri, oauth2.RequestURIPrefix) {
+		if requestObject != "" {
+			return errors.ErrInvalidRequest
+		}
+
+		fn := s.RequestURIFetchHandler
+		if fn == nil {
+			return errors.ErrRequestURINotSupported
+		}
+		v, err := fn(ctx, uri)
+		if err != nil || v == "" {
+			return errors.ErrInvalidRequestURI
+		}
+		requestObject = v
+	}
+	if requestObject == "" {
+		return nil
+	}
+
+	clientID := r.FormValue("client_id")
+	if clientID == "" {
+		return errors.ErrInvalidRequest
+	}
+
+	keys, err := s.clientKeys(ctx, clientID)
+	if err != nil {
+		return err
+	} else if keys == nil {
+		return errors.ErrInvalidRequestObject
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(requestObject, claims, func(t *jwt.Token) (interface{}, error) {
+		return verificationKeys(keys, t)
+	}, jwt.WithExpirationRequired())
+	if err != nil {
+		return errors.ErrInvalidRequestObject
+	}
+
+	if v, ok := claims["client_id"].(string); !ok || v != clientID {
+		return errors.ErrInvalidRequestObject
+	}
+	if v, ok := claims["iss"]; ok && v != clientID {
+		return errors.ErrInvalidRequestObject
+	}
+	aud, err := claims.GetAudience()
+	if err != nil || !s.validRequestObjectAudience(aud) {
+		return errors.ErrInvalidRequestObject
+	}
+
+	for k, v := range claims {
+		if requestObjectClaims[k] {
+			continue
+		}
+		pv, err := requestObjectParam(v)
+		if err != nil {
+			return errors.ErrInvalidRequestObject
+		}
+		r.Form.Set(k, pv)
+	}
+	return nil
+}
+
+// check the audience of the request object identifies the authorization server
+func (s *Server) validRequestObjectAudience(aud jwt.ClaimStrings) bool {
+	for _, v := range aud {
+		if v == "" {
+			continue
+		}
+		if v == s.Config.Issuer || v == s.Config.AuthorizeEndpoint {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package server_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestRequestObject(t *testing.T) {
+	rsrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.Path {
+		case "/authorize":
+			err = srv.HandleAuthorizeRequest(w, r)
+		case "/token":
+			err = srv.HandleTokenRequest(w, r)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}))
+	defer rsrv.Close()
+	e := httpexpect.New(t, rsrv.URL)
+
+	codes := make(chan string, 1)
+	csrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth2" && r.FormValue("state") == "123" {
+			codes <- r.FormValue("code")
+		}
+	}))
+	defer csrv.Close()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwk, err := models.NewJWK("client-1", "ES256", key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs := store.NewClientStore()
+	cs.Set(clientID, &models.Client{ID: clientID, Secret: clientSecret, Domain: csrv.URL + "/oauth2", JWKS: &models.JWKSet{Keys: []*models.JWK{jwk}}})
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	mgr.MapClientStorage(cs)
+
+	cfg := server.NewConfig()
+	cfg.Issuer = "https://as.example.com"
+	srv = server.NewServer(cfg, mgr)
+	srv.SetUserAuthorizationHandler(func(w http.ResponseWriter, r *http.Request) (string, error) {
+		return "000000", nil
+	})
+
+	requestObject := func(claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+		token.Header["kid"] = "client-1"
+		v, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":           clientID,
+			"aud":           cfg.Issuer,
+			"exp":           time.Now().Add(time.Minute).Unix(),
+			"client_id":     clientID,
+			"response_type": "code",
+			"redirect_uri":  csrv.URL + "/oauth2",
+			"scope":         "all",
+			"state":         "123",
+		}
+	}
+	authorize := func(request string) *httpexpect.Response {
+		return e.GET("/authorize").
+			WithQuery("client_id", clientID).
+			WithQuery("response_type", "code").
+			WithQuery("scope", "admin").
+			WithQuery("request", request).
+			Expect()
+	}
+
+	// the claims of the request object override the query parameters
+	authorize(requestObject(validClaims())).Status(http.StatusOK)
+	code := receiveCode(t, codes)
+
+	e.POST("/token").
+		WithFormField("grant_type", "authorization_code").
+		WithFormField("redirect_uri", csrv.URL+"/oauth2").
+		WithFormField("code", code).
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("scope").Equal("all")
+
+	claims := validClaims()
+	claims["client_id"] = "222222"
+	authorize(requestObject(claims)).Status(http.StatusBadRequest).Body().Contains("invalid_request_object")
+
+	claims = validClaims()
+	claims["aud"] = "https://other.example.com"
+	authorize(requestObject(claims)).Status(http.StatusBadRequest).Body().Contains("invalid_request_object")
+
+	claims = validClaims()
+	claims["exp"] = time.Now().Add(-time.Minute).Unix()
+	authorize(requestObject(claims)).Status(http.StatusBadRequest).Body().Contains("invalid_request_object")
+
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodES256, validClaims()).SignedString(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorize(forged).Status(http.StatusBadRequest).Body().Contains("invalid_request_object")
+
+	// request object by reference
+	e.GET("/authorize").
+		WithQuery("client_id", clientID).
+		WithQuery("request_uri", "https://client.example.com/request.jwt").
+		Expect().
+		Status(http.StatusBadRequest).
+		Body().Contains("request_uri_not_supported")
+
+	srv.SetRequestURIFetchHandler(func(ctx context.Context, requestURI string) (string, error) {
+		return requestObject(validClaims()), nil
+	})
+	e.GET("/authorize").
+		WithQuery("client_id", clientID).
+		WithQuery("request_uri", "https://client.example.com/request.jwt").
+		Expect().
+		Status(http.StatusOK)
+	receiveCode(t, codes)
+}
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"net/url"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// check the resource indicators are absolute URIs without fragment
+// https://tools.ietf.org/html/rfc8707#section-2
+func validResources(resources []string) error {
+	for _, v := range resources {
+		u, err := url.Parse(v)
+		if err != nil || !u.IsAbs() || u.Fragment != "" {
+			return errors.ErrInvalidTarget
+		}
+	}
+	return nil
+}
+
+// ValidationAudience check the token is issued to the audience of the resource server,
+// the token without audience isn't accepted
+func ValidationAudience(ti oauth2.TokenInfo, audience string) error {
+	if ati, ok := ti.(oauth2.AudienceTokenInfo); ok {
+		for _, v := range ati.GetAudience() {
+			if v == audience {
+				return nil
+			}
+		}
+	}
+	return errors.ErrInvalidAccessToken
+}
+
+// check the access token is issued to the configured audience of the resource server
+func (s *Server) validTokenAudience(ti oauth2.TokenInfo) error {
+	if aud := s.Config.Audience; aud != "" {
+		return ValidationAudience(ti, aud)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+)
+
+func TestResourceIndicators(t *testing.T) {
+	tsrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testServer(t, w, r)
+	}))
+	defer tsrv.Close()
+	e := httpexpect.New(t, tsrv.URL)
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	cs := store.NewClientStore()
+	cs.Set(clientID, &models.Client{
+		ID:        clientID,
+		Secret:    clientSecret,
+		Resources: []string{"https://billing.example.com", "https://admin.example.com"},
+	})
+	mgr.MapClientStorage(cs)
+
+	srv = server.NewDefaultServer(mgr)
+
+	for _, resource := range []string{"https://other.example.com", "/billing", "https://billing.example.com#fragment"} {
+		e.POST("/token").
+			WithFormField("grant_type", "client_credentials").
+			WithFormField("resource", resource).
+			WithBasicAuth(clientID, clientSecret).
+			Expect().
+			Status(http.StatusBadRequest).
+			JSON().Object().Value("error").Equal("invalid_target")
+	}
+
+	access := e.POST("/token").
+		WithFormField("grant_type", "client_credentials").
+		WithFormField("resource", "https://billing.example.com").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("access_token").String().Raw()
+
+	validation := func(audience string) error {
+		srv.Config.Audience = audience
+		r := httptest.NewRequest("GET", "/resource", nil)
+		r.Header.Set("Authorization", "Bearer "+access)
+		_, err := srv.ValidationBearerToken(r)
+		return err
+	}
+
+	if err := validation("https://billing.example.com"); err != nil {
+		t.Error(err)
+	}
+	// the token of the billing api can't be used against the admin api
+	if err := validation("https://admin.example.com"); err != errors.ErrInvalidAccessToken {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
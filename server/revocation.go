@@ -0,0 +1,58 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// HandleRevocationRequest the token revocation request handling
+// https://tools.ietf.org/html/rfc7009
+func (s *Server) HandleRevocationRequest(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	if r.Method != "POST" {
+		return s.tokenError(w, errors.ErrInvalidRequest)
+	}
+
+	cli, err := s.authenticateClient(r)
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+
+	token := r.FormValue("token")
+	if token == "" {
+		return s.tokenError(w, errors.ErrInvalidRequest)
+	}
+
+	ti, isRefresh, err := s.loadToken(ctx, token, r.FormValue("token_type_hint"))
+	if err != nil {
+		return s.tokenError(w, err)
+	} else if ti == nil {
+		// invalid tokens do not cause an error response
+		w.WriteHeader(http.StatusOK)
+		return nil
+	} else if ti.GetClientID() != cli.GetID() {
+		return s.tokenError(w, errors.ErrUnauthorizedClient)
+	}
+
+	if isRefresh {
+		// revoking a refresh token also invalidates the access token of the same grant
+		if access := ti.GetAccess(); access != "" {
+			if err := s.Manager.RemoveAccessToken(ctx, access); err != nil {
+				return s.tokenError(w, err)
+			}
+		}
+		err = s.Manager.RemoveRefreshToken(ctx, token)
+	} else {
+		err = s.Manager.RemoveAccessToken(ctx, token)
+	}
+	if err != nil {
+		return s.tokenError(w, err)
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
@@ -0,0 +1,123 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+)
+
+func TestRevocation(t *testing.T) {
+	tsrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testServer(t, w, r)
+	}))
+	defer tsrv.Close()
+	e := httpexpect.New(t, tsrv.URL)
+
+	cs := store.NewClientStore()
+	cs.Set(clientID, &models.Client{ID: clientID, Secret: clientSecret})
+	cs.Set("222222", &models.Client{ID: "222222", Secret: "22222222"})
+	manager.MapClientStorage(cs)
+	srv = server.NewDefaultServer(manager)
+
+	resObj := e.POST("/token").
+		WithFormField("grant_type", "client_credentials").
+		WithFormField("scope", "all").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	access := resObj.Value("access_token").String().Raw()
+
+	e.POST("/revoke").
+		WithFormField("token", access).
+		WithBasicAuth(clientID, "wrong").
+		Expect().
+		Status(http.StatusUnauthorized).
+		JSON().Object().Value("error").Equal("invalid_client")
+
+	e.POST("/revoke").
+		WithFormField("token", access).
+		WithFormField("token_type_hint", "id_token").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Equal("unsupported_token_type")
+
+	e.POST("/revoke").
+		WithFormField("token", access).
+		WithBasicAuth("222222", "22222222").
+		Expect().
+		Status(http.StatusUnauthorized).
+		JSON().Object().Value("error").Equal("unauthorized_client")
+
+	e.POST("/revoke").
+		WithFormField("token", access).
+		WithFormField("token_type_hint", "access_token").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK)
+
+	req := httptest.NewRequest("GET", "http://example.com", nil)
+	req.Header.Set("Authorization", "Bearer "+access)
+	if _, err := srv.ValidationBearerToken(req); err == nil {
+		t.Error("the revoked access token is still valid")
+	}
+
+	// unknown tokens are answered with 200 as well
+	e.POST("/revoke").
+		WithFormField("token", access).
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK)
+}
+
+func TestRevocationRefreshToken(t *testing.T) {
+	tsrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testServer(t, w, r)
+	}))
+	defer tsrv.Close()
+	e := httpexpect.New(t, tsrv.URL)
+
+	manager.MapClientStorage(clientStore("", false))
+	srv = server.NewDefaultServer(manager)
+	srv.SetPasswordAuthorizationHandler(func(_ context.Context, _, username, password string) (string, error) {
+		return "000000", nil
+	})
+
+	resObj := e.POST("/token").
+		WithFormField("grant_type", "password").
+		WithFormField("username", "admin").
+		WithFormField("password", "123456").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	access := resObj.Value("access_token").String().Raw()
+	refresh := resObj.Value("refresh_token").String().Raw()
+
+	e.POST("/revoke").
+		WithFormField("token", refresh).
+		WithFormField("token_type_hint", "refresh_token").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK)
+
+	e.POST("/token").
+		WithFormField("grant_type", "refresh_token").
+		WithFormField("refresh_token", refresh).
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	req := httptest.NewRequest("GET", "http://example.com", nil)
+	req.Header.Set("Authorization", "Bearer "+access)
+	if _, err := srv.ValidationBearerToken(req); err == nil {
+		t.Error("the access token of a revoked refresh token is still valid")
+	}
+}
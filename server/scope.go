@@ -0,0 +1,143 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// NewScopeRegistry create the registry of the scopes known by the server
+func NewScopeRegistry() *ScopeRegistry {
+	return &ScopeRegistry{
+		descriptions: make(map[string]string),
+		hierarchical: make(map[string]bool),
+	}
+}
+
+// ScopeRegistry the scopes known by the server and their descriptions,
+// the unknown scopes are rejected with invalid_scope when the registry is set
+type ScopeRegistry struct {
+	mu           sync.RWMutex
+	names        []string
+	descriptions map[string]string
+	hierarchical map[string]bool
+}
+
+// Register register the known scope and the description shown to the user
+func (sr *ScopeRegistry) Register(name, description string) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if _, ok := sr.descriptions[name]; !ok {
+		sr.names = append(sr.names, name)
+	}
+	sr.descriptions[name] = description
+}
+
+// RegisterHierarchical register the known scope implying its descendants and wildcard matches,
+// e.g. repo implies repo:read and project:*:read implies project:1234:read
+func (sr *ScopeRegistry) RegisterHierarchical(name, description string) {
+	sr.Register(name, description)
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	sr.hierarchical[name] = true
+}
+
+// Match check whether the granted scope token implies the required scope token,
+// only the hierarchical scopes imply the other scope tokens, it's the scope matcher of the manager too
+func (sr *ScopeRegistry) Match(granted, required string) bool {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	return sr.match(granted, required)
+}
+
+func (sr *ScopeRegistry) match(granted, required string) bool {
+	return granted == required || (sr.hierarchical[granted] && oauth2.MatchScope(granted, required))
+}
+
+// Description get the description of the known scope
+func (sr *ScopeRegistry) Description(name string) (string, bool) {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	description, ok := sr.descriptions[name]
+	return description, ok
+}
+
+// Scopes the names of the known scopes in the order they were registered
+func (sr *ScopeRegistry) Scopes() []string {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	return append([]string{}, sr.names...)
+}
+
+// Validate check all scope tokens of the scope are known, the scope token is known
+// when it is registered or implied by a hierarchical scope, e.g. project:1234:read by project:*:read
+func (sr *ScopeRegistry) Validate(scope oauth2.Scope) error {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	if !oauth2.Scope(sr.names).ImpliesAll(scope, sr.match) {
+		return errors.ErrInvalidScope
+	}
+	return nil
+}
+
+// get the scope matcher of the scope registry, the scope tokens only match the same one without the registry
+func (s *Server) scopeMatcher() oauth2.ScopeMatcher {
+	if sr := s.ScopeRegistry; sr != nil {
+		return sr.Match
+	}
+	return nil
+}
+
+// parse and normalize the requested scope, the unknown scope is rejected when the scope registry is set
+func (s *Server) normalizeScope(scope string) (string, error) {
+	ps, err := oauth2.ParseScope(scope)
+	if err != nil {
+		return "", err
+	}
+	if sr := s.ScopeRegistry; sr != nil {
+		if err := sr.Validate(ps); err != nil {
+			return "", err
+		}
+	}
+	return ps.String(), nil
+}
+
+// NewClientScopeHandler create the client scope handler allowing the scope implied by the allowed scope of the client,
+// the scope tokens are compared by the matcher, e.g. the Match of the scope registry, or exactly when it is nil
+func NewClientScopeHandler(allowed func(clientID string) (scope string, err error), match oauth2.ScopeMatcher) ClientScopeHandler {
+	return func(tgr *oauth2.TokenGenerateRequest) (bool, error) {
+		scope, err := allowed(tgr.ClientID)
+		if err != nil {
+			return false, err
+		}
+		granted, _ := oauth2.ParseScope(scope)
+		requested, err := oauth2.ParseScope(tgr.Scope)
+		if err != nil {
+			return false, err
+		}
+		return granted.ImpliesAll(requested, match), nil
+	}
+}
+
+// ValidationScope check the token satisfies the required scope on the resource server,
+// errors.ErrInsufficientScope is returned when a required scope token isn't implied by the scope of the token
+// https://tools.ietf.org/html/rfc6750#section-3.1
+func (s *Server) ValidationScope(ti oauth2.TokenInfo, required string) error {
+	rs, err := oauth2.ParseScope(required)
+	if err != nil {
+		return err
+	}
+	granted, _ := oauth2.ParseScope(ti.GetScope())
+	if !granted.ImpliesAll(rs, s.scopeMatcher()) {
+		return errors.ErrInsufficientScope
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+)
+
+func TestScopeRegistry(t *testing.T) {
+	tsrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testServer(t, w, r)
+	}))
+	defer tsrv.Close()
+	e := httpexpect.New(t, tsrv.URL)
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	mgr.MapClientStorage(clientStore("", false))
+
+	registry := server.NewScopeRegistry()
+	registry.Register("read", "Read your data")
+	registry.Register("write", "Change your data")
+
+	srv = server.NewDefaultServer(mgr)
+	srv.SetScopeRegistry(registry)
+	srv.SetPasswordAuthorizationHandler(func(ctx context.Context, clientID, username, password string) (string, error) {
+		return "000000", nil
+	})
+
+	if v := srv.GetMetadata()["scopes_supported"]; len(v.([]string)) != 2 {
+		t.Fatalf("unexpected scopes_supported: %v", v)
+	}
+
+	for _, scope := range []string{"read admin", `read "write"`} {
+		e.POST("/token").
+			WithFormField("grant_type", "client_credentials").
+			WithFormField("scope", scope).
+			WithBasicAuth(clientID, clientSecret).
+			Expect().
+			Status(http.StatusBadRequest).
+			JSON().Object().Value("error").Equal("invalid_scope")
+	}
+
+	resObj := e.POST("/token").
+		WithFormField("grant_type", "password").
+		WithFormField("username", "admin").
+		WithFormField("password", "123456").
+		WithFormField("scope", " read  read ").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	resObj.Value("scope").Equal("read")
+	refresh := resObj.Value("refresh_token").String().Raw()
+
+	// the refreshed scope can't exceed the granted scope
+	e.POST("/token").
+		WithFormField("grant_type", "refresh_token").
+		WithFormField("refresh_token", refresh).
+		WithFormField("scope", "read write").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Equal("invalid_scope")
+}
+
+func TestValidationScope(t *testing.T) {
+	ti := &models.Token{Scope: "repo project:*:read https://api.example.com"}
+
+	registry := server.NewScopeRegistry()
+	registry.RegisterHierarchical("repo", "Access your repositories")
+	registry.RegisterHierarchical("project:*:read", "Read the projects")
+	registry.Register("https://api.example.com", "Access the api")
+
+	srv = server.NewDefaultServer(manage.NewDefaultManager())
+	// the scope tokens only match the same scope token without the registry
+	if err := srv.ValidationScope(ti, "repo:read"); err != errors.ErrInsufficientScope {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	srv.SetScopeRegistry(registry)
+	for _, required := range []string{"repo:read", "repo:write project:1234:read", "https://api.example.com"} {
+		if err := srv.ValidationScope(ti, required); err != nil {
+			t.Errorf("scope %s not satisfied: %v", required, err)
+		}
+	}
+	for _, required := range []string{"project:1234:write", "https://api.example.com/admin"} {
+		if err := srv.ValidationScope(ti, required); err != errors.ErrInsufficientScope {
+			t.Errorf("unexpected error of %s: %v", required, err)
+		}
+	}
+
+	fn := server.NewClientScopeHandler(func(clientID string) (string, error) {
+		return "repo", nil
+	}, registry.Match)
+	if allowed, err := fn(&oauth2.TokenGenerateRequest{ClientID: clientID, Scope: "repo:read"}); err != nil || !allowed {
+		t.Errorf("scope not allowed: %v", err)
+	}
+	if allowed, _ := fn(&oauth2.TokenGenerateRequest{ClientID: clientID, Scope: "admin"}); allowed {
+		t.Error("unexpected scope allowed")
+	}
+
+	if err := registry.Validate(oauth2.NewScope("project:1234:read", "repo:write")); err != nil {
+		t.Error(err)
+	}
+	for _, scope := range []string{"project:1234:write", "https://api.example.com/admin"} {
+		if err := registry.Validate(oauth2.NewScope(scope)); err != errors.ErrInvalidScope {
+			t.Errorf("unexpected error of %s: %v", scope, err)
+		}
+	}
+}
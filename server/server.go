@@ -20,14 +20,16 @@ func NewDefaultServer(manager oauth2.Manager) *Server {
 // NewServer create authorization server
 func NewServer(cfg *Config, manager oauth2.Manager) *Server {
 	srv := &Server{
-		Config:  cfg,
-		Manager: manager,
+		Config:   cfg,
+		Manager:  manager,
+		JTIStore: newMemoryJTIStore(),
 	}
 
 	// default handlers
 	srv.ClientInfoHandler = ClientBasicHandler
 	srv.RefreshTokenResolveHandler = RefreshTokenFormResolveHandler
 	srv.AccessTokenResolveHandler = AccessTokenDefaultResolveHandler
+	srv.TokenExchangeHandler = TokenExchangeScopeHandler
 
 	srv.UserAuthorizationHandler = func(w http.ResponseWriter, r *http.Request) (string, error) {
 		return "", errors.ErrAccessDenied
@@ -36,6 +38,10 @@ func NewServer(cfg *Config, manager oauth2.Manager) *Server {
 	srv.PasswordAuthorizationHandler = func(ctx context.Context, clientID, username, password string) (string, error) {
 		return "", errors.ErrAccessDenied
 	}
+
+	srv.AssertionUserHandler = func(ctx context.Context, clientID, issuer, subject string) (string, error) {
+		return "", errors.ErrAccessDenied
+	}
 	return srv
 }
 
@@ -43,12 +49,23 @@ func NewServer(cfg *Config, manager oauth2.Manager) *Server {
 type Server struct {
 	Config                       *Config
 	Manager                      oauth2.Manager
+	KeySet                       KeySet
+	JTIStore                     oauth2.JTIStore
+	ScopeRegistry                *ScopeRegistry
+	AuthorizationDetailsRegistry *AuthorizationDetailsRegistry
 	ClientInfoHandler            ClientInfoHandler
 	ClientAuthorizedHandler      ClientAuthorizedHandler
 	ClientScopeHandler           ClientScopeHandler
 	UserAuthorizationHandler     UserAuthorizationHandler
+	UserConsentHandler           UserConsentHandler
 	PasswordAuthorizationHandler PasswordAuthorizationHandler
+	IssuerKeysHandler            IssuerKeysHandler
+	ClientKeysHandler            ClientKeysHandler
+	RequestURIFetchHandler       RequestURIFetchHandler
+	AssertionUserHandler         AssertionUserHandler
+	RegistrationAccessHandler    RegistrationAccessHandler
 	RefreshingValidationHandler  RefreshingValidationHandler
+	TokenExchangeHandler         TokenExchangeHandler
 	PreRedirectErrorHandler      PreRedirectErrorHandler
 	RefreshingScopeHandler       RefreshingScopeHandler
 	ResponseErrorHandler         ResponseErrorHandler
@@ -59,6 +76,8 @@ type Server struct {
 	ResponseTokenHandler         ResponseTokenHandler
 	RefreshTokenResolveHandler   RefreshTokenResolveHandler
 	AccessTokenResolveHandler    AccessTokenResolveHandler
+
+	grantHandlers map[oauth2.GrantType]*grantHandler
 }
 
 func (s *Server) handleError(w http.ResponseWriter, req *AuthorizeRequest, err error) error {
@@ -70,7 +89,8 @@ func (s *Server) handleError(w http.ResponseWriter, req *AuthorizeRequest, err e
 }
 
 func (s *Server) redirectError(w http.ResponseWriter, req *AuthorizeRequest, err error) error {
-	if req == nil {
+	// the error is never redirected to the invalid redirect uri
+	if req == nil || err == errors.ErrInvalidRedirectURI {
 		return err
 	}
 
@@ -168,6 +188,14 @@ func (s *Server) CheckCodeChallengeMethod(ccm oauth2.CodeChallengeMethod) bool {
 
 // ValidationAuthorizeRequest the authorization request validation
 func (s *Server) ValidationAuthorizeRequest(r *http.Request) (*AuthorizeRequest, error) {
+	requestURI, err := s.resolvePushedRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resolveRequestObject(r); err != nil {
+		return nil, err
+	}
+
 	redirectURI := r.FormValue("redirect_uri")
 	clientID := r.FormValue("client_id")
 	if !(r.Method == "GET" || r.Method == "POST") ||
@@ -183,8 +211,13 @@ func (s *Server) ValidationAuthorizeRequest(r *http.Request) (*AuthorizeRequest,
 	}
 
 	cc := r.FormValue("code_challenge")
-	if cc == "" && s.Config.ForcePKCE {
-		return nil, errors.ErrCodeChallengeRquired
+	if cc == "" {
+		required, err := s.pkceRequired(r.Context(), clientID)
+		if err != nil {
+			return nil, err
+		} else if required {
+			return nil, errors.ErrCodeChallengeRquired
+		}
 	}
 	if cc != "" && (len(cc) < 43 || len(cc) > 128) {
 		return nil, errors.ErrInvalidCodeChallengeLen
@@ -199,15 +232,24 @@ func (s *Server) ValidationAuthorizeRequest(r *http.Request) (*AuthorizeRequest,
 		return nil, errors.ErrUnsupportedCodeChallengeMethod
 	}
 
+	details, err := s.parseAuthorizationDetails(r.Context(), clientID, r.FormValue("authorization_details"))
+	if err != nil {
+		return nil, err
+	}
+
 	req := &AuthorizeRequest{
-		RedirectURI:         redirectURI,
-		ResponseType:        resType,
-		ClientID:            clientID,
-		State:               r.FormValue("state"),
-		Scope:               r.FormValue("scope"),
-		Request:             r,
-		CodeChallenge:       cc,
-		CodeChallengeMethod: ccm,
+		RedirectURI:          redirectURI,
+		ResponseType:         resType,
+		ClientID:             clientID,
+		State:                r.FormValue("state"),
+		Scope:                r.FormValue("scope"),
+		Request:              r,
+		CodeChallenge:        cc,
+		CodeChallengeMethod:  ccm,
+		Nonce:                r.FormValue("nonce"),
+		RequestURI:           requestURI,
+		Resource:             r.Form["resource"],
+		AuthorizationDetails: details,
 	}
 	return req, nil
 }
@@ -230,12 +272,14 @@ func (s *Server) GetAuthorizeToken(ctx context.Context, req *AuthorizeRequest) (
 	}
 
 	tgr := &oauth2.TokenGenerateRequest{
-		ClientID:       req.ClientID,
-		UserID:         req.UserID,
-		RedirectURI:    req.RedirectURI,
-		Scope:          req.Scope,
-		AccessTokenExp: req.AccessTokenExp,
-		Request:        req.Request,
+		ClientID:             req.ClientID,
+		UserID:               req.UserID,
+		RedirectURI:          req.RedirectURI,
+		Scope:                req.Scope,
+		Resource:             req.Resource,
+		AuthorizationDetails: req.AuthorizationDetails,
+		AccessTokenExp:       req.AccessTokenExp,
+		Request:              req.Request,
 	}
 
 	// check the client allows the authorized scope
@@ -250,6 +294,8 @@ func (s *Server) GetAuthorizeToken(ctx context.Context, req *AuthorizeRequest) (
 
 	tgr.CodeChallenge = req.CodeChallenge
 	tgr.CodeChallengeMethod = req.CodeChallengeMethod
+	tgr.Nonce = req.Nonce
+	tgr.AuthTime = req.AuthTime
 
 	return s.Manager.GenerateAuthToken(ctx, req.ResponseType, tgr)
 }
@@ -273,6 +319,22 @@ func (s *Server) HandleAuthorizeRequest(w http.ResponseWriter, r *http.Request)
 		return s.handleError(w, req, err)
 	}
 
+	// the requested scope must be well-formed and known by the server
+	if req.Scope, err = s.normalizeScope(req.Scope); err != nil {
+		return s.handleError(w, req, err)
+	} else if err := validResources(req.Resource); err != nil {
+		return s.handleError(w, req, err)
+	}
+
+	if req.RequestURI == "" {
+		required, err := s.pushedRequestRequired(ctx, req.ClientID)
+		if err != nil {
+			return s.handleError(w, nil, err)
+		} else if required {
+			return s.handleError(w, nil, errors.ErrInvalidRequest)
+		}
+	}
+
 	// user authorization
 	userID, err := s.UserAuthorizationHandler(w, r)
 	if err != nil {
@@ -281,6 +343,9 @@ func (s *Server) HandleAuthorizeRequest(w http.ResponseWriter, r *http.Request)
 		return nil
 	}
 	req.UserID = userID
+	if req.AuthTime.IsZero() {
+		req.AuthTime = time.Now()
+	}
 
 	// specify the scope of authorization
 	if fn := s.AuthorizeScopeHandler; fn != nil {
@@ -292,6 +357,16 @@ func (s *Server) HandleAuthorizeRequest(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	// ask the user to consent the scope not granted to the client yet
+	if fn := s.UserConsentHandler; fn != nil {
+		consented, err := s.userConsent(w, r, req, fn)
+		if err != nil {
+			return s.handleError(w, req, err)
+		} else if !consented {
+			return nil
+		}
+	}
+
 	// specify the expiration time of access token
 	if fn := s.AccessTokenExpHandler; fn != nil {
 		exp, err := fn(w, r)
@@ -306,13 +381,21 @@ func (s *Server) HandleAuthorizeRequest(w http.ResponseWriter, r *http.Request)
 		return s.handleError(w, req, err)
 	}
 
+	// the request uri of the pushed authorization request is used only once
+	if req.RequestURI != "" {
+		if pm, ok := s.Manager.(oauth2.PushedRequestManager); ok {
+			if err := pm.RemovePushedRequest(ctx, req.RequestURI); err != nil {
+				return err
+			}
+		}
+	}
+
 	// If the redirect URI is empty, the default domain provided by the client is used.
 	if req.RedirectURI == "" {
-		client, err := s.Manager.GetClient(ctx, req.ClientID)
+		req.RedirectURI, err = s.defaultRedirectURI(ctx, req.ClientID)
 		if err != nil {
 			return err
 		}
-		req.RedirectURI = client.GetDomain()
 	}
 
 	return s.redirect(w, req, s.GetAuthorizeData(req.ResponseType, ti))
@@ -330,15 +413,37 @@ func (s *Server) ValidationTokenRequest(r *http.Request) (oauth2.GrantType, *oau
 		return "", nil, errors.ErrUnsupportedGrantType
 	}
 
-	clientID, clientSecret, err := s.ClientInfoHandler(r)
+	clientID, clientSecret, method, err := s.clientInfo(r)
 	if err != nil {
 		return "", nil, err
+	} else if err := s.checkClientAuthMethod(r, clientID, clientSecret, method); err != nil {
+		return "", nil, err
 	}
 
 	tgr := &oauth2.TokenGenerateRequest{
-		ClientID:     clientID,
-		ClientSecret: clientSecret,
-		Request:      r,
+		ClientID:         clientID,
+		ClientSecret:     clientSecret,
+		ClientAuthMethod: method,
+		Request:          r,
+	}
+
+	// the issued token is bound to the key of the DPoP proof
+	jkt, err := s.verifyDPoPProof(r, "")
+	if err != nil {
+		return "", nil, err
+	} else if jkt != "" {
+		tgr.Confirmation = &oauth2.Confirmation{JKT: jkt}
+	}
+
+	// and to the client certificate of the mutual TLS connection
+	cert, _, err := s.clientCertificate(r)
+	if err != nil {
+		return "", nil, err
+	} else if cert != nil {
+		if tgr.Confirmation == nil {
+			tgr.Confirmation = &oauth2.Confirmation{}
+		}
+		tgr.Confirmation.X5TS256 = certificateThumbprint(cert)
 	}
 
 	switch gt {
@@ -350,8 +455,13 @@ func (s *Server) ValidationTokenRequest(r *http.Request) (oauth2.GrantType, *oau
 			return "", nil, errors.ErrInvalidRequest
 		}
 		tgr.CodeVerifier = r.FormValue("code_verifier")
-		if s.Config.ForcePKCE && tgr.CodeVerifier == "" {
-			return "", nil, errors.ErrInvalidRequest
+		if tgr.CodeVerifier == "" {
+			required, err := s.pkceRequired(r.Context(), clientID)
+			if err != nil {
+				return "", nil, err
+			} else if required {
+				return "", nil, errors.ErrInvalidRequest
+			}
 		}
 	case oauth2.PasswordCredentials:
 		tgr.Scope = r.FormValue("scope")
@@ -375,6 +485,66 @@ func (s *Server) ValidationTokenRequest(r *http.Request) (oauth2.GrantType, *oau
 		if err != nil {
 			return "", nil, err
 		}
+	case oauth2.DeviceCode:
+		tgr.DeviceCode = r.FormValue("device_code")
+		if tgr.DeviceCode == "" {
+			return "", nil, errors.ErrInvalidRequest
+		}
+	case oauth2.TokenExchange:
+		tgr.Scope = r.FormValue("scope")
+		tgr.SubjectToken = r.FormValue("subject_token")
+		tgr.SubjectTokenType = r.FormValue("subject_token_type")
+		if tgr.SubjectToken == "" || tgr.SubjectTokenType == "" {
+			return "", nil, errors.ErrInvalidRequest
+		}
+		tgr.ActorToken = r.FormValue("actor_token")
+		tgr.ActorTokenType = r.FormValue("actor_token_type")
+		if (tgr.ActorToken == "") != (tgr.ActorTokenType == "") {
+			return "", nil, errors.ErrInvalidRequest
+		}
+		tgr.RequestedTokenType = r.FormValue("requested_token_type")
+		tgr.Audience = r.Form["audience"]
+	case oauth2.JWTBearer:
+		tgr.Scope = r.FormValue("scope")
+		tgr.Assertion = r.FormValue("assertion")
+		if tgr.Assertion == "" {
+			return "", nil, errors.ErrInvalidRequest
+		}
+
+		claims, err := s.verifyAssertion(r.Context(), tgr.Assertion, s.IssuerKeysHandler)
+		if err != nil {
+			return "", nil, err
+		}
+
+		userID, err := s.AssertionUserHandler(r.Context(), clientID, claims.Issuer, claims.Subject)
+		if err != nil {
+			return "", nil, err
+		} else if userID == "" {
+			return "", nil, errors.ErrInvalidGrant
+		}
+		tgr.UserID = userID
+	default:
+		if h, ok := s.grantHandlers[gt]; ok && h.request != nil {
+			if err := h.request(r, tgr); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+
+	if tgr.Scope, err = s.normalizeScope(tgr.Scope); err != nil {
+		return "", nil, err
+	}
+
+	// the resource indicators of the requested access token
+	tgr.Resource = r.Form["resource"]
+	if err := validResources(tgr.Resource); err != nil {
+		return "", nil, err
+	}
+
+	// the authorization details of the requested access token
+	tgr.AuthorizationDetails, err = s.parseAuthorizationDetails(r.Context(), tgr.ClientID, r.FormValue("authorization_details"))
+	if err != nil {
+		return "", nil, err
 	}
 	return gt, tgr, nil
 }
@@ -419,7 +589,22 @@ func (s *Server) GetAccessToken(ctx context.Context, gt oauth2.GrantType, tgr *o
 			}
 		}
 		return ti, nil
-	case oauth2.PasswordCredentials, oauth2.ClientCredentials:
+	case oauth2.DeviceCode:
+		ti, err := s.Manager.GenerateAccessToken(ctx, gt, tgr)
+		if err != nil {
+			switch err {
+			case errors.ErrInvalidDeviceCode:
+				return nil, errors.ErrInvalidGrant
+			case errors.ErrInvalidClient:
+				return nil, errors.ErrInvalidClient
+			default:
+				return nil, err
+			}
+		}
+		return ti, nil
+	case oauth2.TokenExchange:
+		return s.exchangeToken(ctx, tgr)
+	case oauth2.PasswordCredentials, oauth2.ClientCredentials, oauth2.JWTBearer:
 		if fn := s.ClientScopeHandler; fn != nil {
 			allowed, err := fn(tgr)
 			if err != nil {
@@ -430,37 +615,18 @@ func (s *Server) GetAccessToken(ctx context.Context, gt oauth2.GrantType, tgr *o
 		}
 		return s.Manager.GenerateAccessToken(ctx, gt, tgr)
 	case oauth2.Refreshing:
-		// check scope
-		if scopeFn := s.RefreshingScopeHandler; tgr.Scope != "" && scopeFn != nil {
-			rti, err := s.Manager.LoadRefreshToken(ctx, tgr.Refresh)
-			if err != nil {
-				if err == errors.ErrInvalidRefreshToken || err == errors.ErrExpiredRefreshToken {
-					return nil, errors.ErrInvalidGrant
-				}
-				return nil, err
-			}
-
-			allowed, err := scopeFn(tgr, rti.GetScope())
-			if err != nil {
-				return nil, err
-			} else if !allowed {
-				return nil, errors.ErrInvalidScope
-			}
+		// the unknown refresh token may be a rotated one,
+		// the manager detects its reuse when the token family is stored
+		rti, err := s.Manager.LoadRefreshToken(ctx, tgr.Refresh)
+		if err == errors.ErrExpiredRefreshToken {
+			return nil, errors.ErrInvalidGrant
+		} else if err != nil && err != errors.ErrInvalidRefreshToken {
+			return nil, err
 		}
 
-		if validationFn := s.RefreshingValidationHandler; validationFn != nil {
-			rti, err := s.Manager.LoadRefreshToken(ctx, tgr.Refresh)
-			if err != nil {
-				if err == errors.ErrInvalidRefreshToken || err == errors.ErrExpiredRefreshToken {
-					return nil, errors.ErrInvalidGrant
-				}
-				return nil, err
-			}
-			allowed, err := validationFn(rti)
-			if err != nil {
+		if rti != nil {
+			if err := s.validRefreshRequest(ctx, tgr, rti); err != nil {
 				return nil, err
-			} else if !allowed {
-				return nil, errors.ErrInvalidScope
 			}
 		}
 
@@ -474,9 +640,39 @@ func (s *Server) GetAccessToken(ctx context.Context, gt oauth2.GrantType, tgr *o
 		return ti, nil
 	}
 
+	if h, ok := s.grantHandlers[gt]; ok {
+		return s.generateGrantToken(ctx, gt, tgr, h)
+	}
 	return nil, errors.ErrUnsupportedGrantType
 }
 
+// check the refresh request against the refresh token information
+func (s *Server) validRefreshRequest(ctx context.Context, tgr *oauth2.TokenGenerateRequest, rti oauth2.TokenInfo) error {
+	if err := s.validBoundRefresh(ctx, tgr, rti); err != nil {
+		return err
+	}
+
+	// check scope
+	if scopeFn := s.RefreshingScopeHandler; tgr.Scope != "" && scopeFn != nil {
+		allowed, err := scopeFn(tgr, rti.GetScope())
+		if err != nil {
+			return err
+		} else if !allowed {
+			return errors.ErrInvalidScope
+		}
+	}
+
+	if validationFn := s.RefreshingValidationHandler; validationFn != nil {
+		allowed, err := validationFn(rti)
+		if err != nil {
+			return err
+		} else if !allowed {
+			return errors.ErrInvalidScope
+		}
+	}
+	return nil
+}
+
 // GetTokenData token data
 func (s *Server) GetTokenData(ti oauth2.TokenInfo) map[string]interface{} {
 	data := map[string]interface{}{
@@ -485,6 +681,10 @@ func (s *Server) GetTokenData(ti oauth2.TokenInfo) map[string]interface{} {
 		"expires_in":   int64(ti.GetAccessExpiresIn() / time.Second),
 	}
 
+	if cnf := tokenConfirmation(ti); cnf != nil && cnf.JKT != "" {
+		data["token_type"] = DPoPTokenType
+	}
+
 	if scope := ti.GetScope(); scope != "" {
 		data["scope"] = scope
 	}
@@ -493,6 +693,14 @@ func (s *Server) GetTokenData(ti oauth2.TokenInfo) map[string]interface{} {
 		data["refresh_token"] = refresh
 	}
 
+	if details := tokenAuthorizationDetails(ti); len(details) > 0 {
+		data["authorization_details"] = details
+	}
+
+	if oti, ok := ti.(oauth2.OpenIDTokenInfo); ok && oti.GetIDToken() != "" {
+		data["id_token"] = oti.GetIDToken()
+	}
+
 	if fn := s.ExtensionFieldsHandler; fn != nil {
 		ext := fn(ti)
 		for k, v := range ext {
@@ -519,7 +727,11 @@ func (s *Server) HandleTokenRequest(w http.ResponseWriter, r *http.Request) erro
 		return s.tokenError(w, err)
 	}
 
-	return s.token(w, s.GetTokenData(ti), nil)
+	data := s.GetTokenData(ti)
+	if gt == oauth2.TokenExchange {
+		data["issued_token_type"] = oauth2.AccessTokenType
+	}
+	return s.token(w, data, nil)
 }
 
 // GetErrorData get error response data
@@ -582,5 +794,83 @@ func (s *Server) ValidationBearerToken(r *http.Request) (oauth2.TokenInfo, error
 		return nil, errors.ErrInvalidAccessToken
 	}
 
-	return s.Manager.LoadAccessToken(ctx, accessToken)
+	ti, err := s.Manager.LoadAccessToken(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// the DPoP-bound access token is never accepted as bearer token
+	if cnf := tokenConfirmation(ti); cnf != nil && cnf.JKT != "" {
+		return nil, errors.ErrInvalidAccessToken
+	} else if err := s.validCertificateBinding(r, cnf); err != nil {
+		return nil, err
+	} else if err := s.validTokenAudience(ti); err != nil {
+		return nil, err
+	}
+	return ti, nil
+}
+
+// authenticate the client of the request with the client info handler
+func (s *Server) authenticateClient(r *http.Request) (oauth2.ClientInfo, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, errors.ErrInvalidRequest
+	}
+
+	clientID, clientSecret, method, err := s.clientInfo(r)
+	if err != nil {
+		return nil, err
+	} else if err := s.checkClientAuthMethod(r, clientID, clientSecret, method); err != nil {
+		return nil, err
+	}
+
+	cli, err := s.Manager.GetClient(r.Context(), clientID)
+	if err != nil {
+		return nil, errors.ErrInvalidClient
+	}
+
+	if method != "" {
+		return cli, nil
+	} else if cliPass, ok := cli.(oauth2.ClientPasswordVerifier); ok {
+		if !cliPass.VerifyPassword(clientSecret) {
+			return nil, errors.ErrInvalidClient
+		}
+	} else if len(cli.GetSecret()) > 0 && clientSecret != cli.GetSecret() {
+		return nil, errors.ErrInvalidClient
+	}
+	return cli, nil
+}
+
+// load the token information of an access or refresh token,
+// the hint decides which kind of token is looked up first
+func (s *Server) loadToken(ctx context.Context, token, hint string) (oauth2.TokenInfo, bool, error) {
+	lookups := []bool{false, true}
+	switch hint {
+	case "", "access_token":
+	case "refresh_token":
+		lookups = []bool{true, false}
+	default:
+		return nil, false, errors.ErrUnsupportedTokenType
+	}
+
+	for _, isRefresh := range lookups {
+		var (
+			ti  oauth2.TokenInfo
+			err error
+		)
+		if isRefresh {
+			ti, err = s.Manager.LoadRefreshToken(ctx, token)
+		} else {
+			ti, err = s.Manager.LoadAccessToken(ctx, token)
+		}
+
+		switch err {
+		case nil:
+			return ti, isRefresh, nil
+		case errors.ErrInvalidAccessToken, errors.ErrExpiredAccessToken,
+			errors.ErrInvalidRefreshToken, errors.ErrExpiredRefreshToken:
+		default:
+			return nil, false, err
+		}
+	}
+	return nil, false, nil
 }
@@ -24,6 +24,11 @@ func (s *Server) SetAllowedGrantType(types ...oauth2.GrantType) {
 	s.Config.AllowedGrantTypes = types
 }
 
+// SetKeySet the public keys published by the json web key set endpoint
+func (s *Server) SetKeySet(keySet KeySet) {
+	s.KeySet = keySet
+}
+
 // SetClientInfoHandler get client info from request
 func (s *Server) SetClientInfoHandler(handler ClientInfoHandler) {
 	s.ClientInfoHandler = handler
@@ -44,6 +49,22 @@ func (s *Server) SetUserAuthorizationHandler(handler UserAuthorizationHandler) {
 	s.UserAuthorizationHandler = handler
 }
 
+// SetScopeRegistry set the registry of the known scopes, the unknown scopes are rejected
+func (s *Server) SetScopeRegistry(registry *ScopeRegistry) {
+	s.ScopeRegistry = registry
+}
+
+// SetAuthorizationDetailsRegistry set the registry of the known authorization details types,
+// the authorization details are rejected when the registry isn't set
+func (s *Server) SetAuthorizationDetailsRegistry(registry *AuthorizationDetailsRegistry) {
+	s.AuthorizationDetailsRegistry = registry
+}
+
+// SetUserConsentHandler ask the user to consent the scope not granted to the client yet
+func (s *Server) SetUserConsentHandler(handler UserConsentHandler) {
+	s.UserConsentHandler = handler
+}
+
 // SetPasswordAuthorizationHandler get user id from username and password
 func (s *Server) SetPasswordAuthorizationHandler(handler PasswordAuthorizationHandler) {
 	s.PasswordAuthorizationHandler = handler
@@ -74,6 +95,42 @@ func (s *Server) SetPreRedirectErrorHandler(handler PreRedirectErrorHandler) {
 	s.PreRedirectErrorHandler = handler
 }
 
+// SetTokenExchangeHandler check the token exchange is allowed, and the scope and audience of the new token
+func (s *Server) SetTokenExchangeHandler(handler TokenExchangeHandler) {
+	s.TokenExchangeHandler = handler
+}
+
+// SetIssuerKeysHandler get the public keys of the trusted jwt issuer
+func (s *Server) SetIssuerKeysHandler(handler IssuerKeysHandler) {
+	s.IssuerKeysHandler = handler
+}
+
+// SetClientKeysHandler get the public keys of the client
+func (s *Server) SetClientKeysHandler(handler ClientKeysHandler) {
+	s.ClientKeysHandler = handler
+}
+
+// SetRequestURIFetchHandler fetch the request object referenced by the request uri
+func (s *Server) SetRequestURIFetchHandler(handler RequestURIFetchHandler) {
+	s.RequestURIFetchHandler = handler
+}
+
+// SetRegistrationAccessHandler check the client registration request is authorized
+func (s *Server) SetRegistrationAccessHandler(handler RegistrationAccessHandler) {
+	s.RegistrationAccessHandler = handler
+}
+
+// SetAssertionUserHandler get user id from the subject of the jwt assertion
+func (s *Server) SetAssertionUserHandler(handler AssertionUserHandler) {
+	s.AssertionUserHandler = handler
+}
+
+// SetJTIStore set the used jwt id store to detect the replayed jwt, the used jwt ids are kept in memory by default,
+// the shared store is needed when several server instances verify the jwts
+func (s *Server) SetJTIStore(stor oauth2.JTIStore) {
+	s.JTIStore = stor
+}
+
 // SetExtensionFieldsHandler in response to the access token with the extension of the field
 func (s *Server) SetExtensionFieldsHandler(handler ExtensionFieldsHandler) {
 	s.ExtensionFieldsHandler = handler
@@ -65,6 +65,26 @@ func testServer(t *testing.T, w http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			t.Error(err)
 		}
+	case "/revoke":
+		err := srv.HandleRevocationRequest(w, r)
+		if err != nil {
+			t.Error(err)
+		}
+	case "/introspect":
+		err := srv.HandleIntrospectionRequest(w, r)
+		if err != nil {
+			t.Error(err)
+		}
+	case "/device_authorization":
+		err := srv.HandleDeviceAuthorizationRequest(w, r)
+		if err != nil {
+			t.Error(err)
+		}
+	case "/device":
+		err := srv.HandleDeviceVerificationRequest(w, r)
+		if err != nil {
+			t.Error(err)
+		}
 	}
 }
 
@@ -102,7 +122,7 @@ func TestAuthorizeCode(t *testing.T) {
 	}))
 	defer csrv.Close()
 
-	manager.MapClientStorage(clientStore(csrv.URL, true))
+	manager.MapClientStorage(clientStore(csrv.URL+"/oauth2", true))
 	srv = server.NewDefaultServer(manager)
 	srv.SetUserAuthorizationHandler(func(w http.ResponseWriter, r *http.Request) (userID string, err error) {
 		userID = "000000"
@@ -153,7 +173,7 @@ func TestAuthorizeCodeWithChallengePlain(t *testing.T) {
 	}))
 	defer csrv.Close()
 
-	manager.MapClientStorage(clientStore(csrv.URL, true))
+	manager.MapClientStorage(clientStore(csrv.URL+"/oauth2", true))
 	srv = server.NewDefaultServer(manager)
 	srv.SetUserAuthorizationHandler(func(w http.ResponseWriter, r *http.Request) (userID string, err error) {
 		userID = "000000"
@@ -206,7 +226,7 @@ func TestAuthorizeCodeWithChallengeS256(t *testing.T) {
 	}))
 	defer csrv.Close()
 
-	manager.MapClientStorage(clientStore(csrv.URL, true))
+	manager.MapClientStorage(clientStore(csrv.URL+"/oauth2", true))
 	srv = server.NewDefaultServer(manager)
 	srv.SetUserAuthorizationHandler(func(w http.ResponseWriter, r *http.Request) (userID string, err error) {
 		userID = "000000"
@@ -235,7 +255,7 @@ func TestImplicit(t *testing.T) {
 	csrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
 	defer csrv.Close()
 
-	manager.MapClientStorage(clientStore(csrv.URL, false))
+	manager.MapClientStorage(clientStore(csrv.URL+"/oauth2", false))
 	srv = server.NewDefaultServer(manager)
 	srv.SetUserAuthorizationHandler(func(w http.ResponseWriter, r *http.Request) (userID string, err error) {
 		userID = "000000"
@@ -381,7 +401,7 @@ func TestRefreshing(t *testing.T) {
 	}))
 	defer csrv.Close()
 
-	manager.MapClientStorage(clientStore(csrv.URL, true))
+	manager.MapClientStorage(clientStore(csrv.URL+"/oauth2", true))
 	srv = server.NewDefaultServer(manager)
 	srv.SetUserAuthorizationHandler(func(w http.ResponseWriter, r *http.Request) (userID string, err error) {
 		userID = "000000"
@@ -391,7 +411,7 @@ func TestRefreshing(t *testing.T) {
 	e.GET("/authorize").
 		WithQuery("response_type", "code").
 		WithQuery("client_id", clientID).
-		WithQuery("scope", "all").
+		WithQuery("scope", "all one").
 		WithQuery("state", "123").
 		WithQuery("redirect_uri", csrv.URL+"/oauth2").
 		Expect().Status(http.StatusOK)
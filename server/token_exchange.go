@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+)
+
+// TokenExchangeScopeHandler allow the token exchange
+// when the requested scope is covered by the scope of the subject token,
+// and the requested audience is within the audience of the subject token when it is restricted
+func TokenExchangeScopeHandler(ctx context.Context, tgr *oauth2.TokenGenerateRequest, subject, actor oauth2.TokenInfo) (bool, error) {
+	granted, _ := oauth2.ParseScope(subject.GetScope())
+	requested, err := oauth2.ParseScope(tgr.Scope)
+	if err != nil || !granted.Contains(requested) {
+		return false, errors.ErrInvalidScope
+	}
+	if aud := tokenAudience(subject); len(aud) > 0 && !oauth2.WithinAudience(aud, tgr.Audience) {
+		return false, errors.ErrInvalidTarget
+	}
+	return true, nil
+}
+
+// get the audience of the subject or actor token
+func tokenAudience(ti oauth2.TokenInfo) []string {
+	if ati, ok := ti.(oauth2.AudienceTokenInfo); ok {
+		return ati.GetAudience()
+	}
+	return nil
+}
+
+// load the token information of the subject or actor token
+func (s *Server) loadExchangeToken(ctx context.Context, token, tokenType string) (oauth2.TokenInfo, error) {
+	var (
+		ti  oauth2.TokenInfo
+		err error
+	)
+	switch tokenType {
+	case oauth2.AccessTokenType:
+		ti, err = s.Manager.LoadAccessToken(ctx, token)
+	case oauth2.RefreshTokenType:
+		ti, err = s.Manager.LoadRefreshToken(ctx, token)
+	default:
+		return nil, errors.ErrInvalidRequest
+	}
+
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidAccessToken, errors.ErrExpiredAccessToken,
+			errors.ErrInvalidRefreshToken, errors.ErrExpiredRefreshToken:
+			return nil, errors.ErrInvalidRequest
+		}
+		return nil, err
+	}
+	return ti, nil
+}
+
+// check the subject or actor token bound to a key or a certificate is presented by its holder,
+// the DPoP proof and the client certificate of the token request must match the confirmation of the token
+func validExchangeBinding(tgr *oauth2.TokenGenerateRequest, ti oauth2.TokenInfo) error {
+	cnf := tokenConfirmation(ti)
+	if cnf == nil || (cnf.JKT == "" && cnf.X5TS256 == "") {
+		return nil
+	}
+
+	bound := tgr.Confirmation
+	if bound == nil ||
+		(cnf.JKT != "" && bound.JKT != cnf.JKT) ||
+		(cnf.X5TS256 != "" && bound.X5TS256 != cnf.X5TS256) {
+		return errors.ErrInvalidRequest
+	}
+	return nil
+}
+
+// exchange the subject token for a new access token
+// https://tools.ietf.org/html/rfc8693#section-2
+func (s *Server) exchangeToken(ctx context.Context, tgr *oauth2.TokenGenerateRequest) (oauth2.TokenInfo, error) {
+	if rtt := tgr.RequestedTokenType; rtt != "" && rtt != oauth2.AccessTokenType {
+		return nil, errors.ErrInvalidRequest
+	}
+
+	subject, err := s.loadExchangeToken(ctx, tgr.SubjectToken, tgr.SubjectTokenType)
+	if err != nil {
+		return nil, err
+	} else if err := validExchangeBinding(tgr, subject); err != nil {
+		return nil, err
+	}
+
+	var actor oauth2.TokenInfo
+	if tgr.ActorToken != "" {
+		actor, err = s.loadExchangeToken(ctx, tgr.ActorToken, tgr.ActorTokenType)
+		if err != nil {
+			return nil, err
+		} else if err := validExchangeBinding(tgr, actor); err != nil {
+			return nil, err
+		}
+
+		// the actor of the subject token is the prior actor of the delegation chain
+		tgr.Actor = &oauth2.Actor{
+			Subject:  actor.GetUserID(),
+			ClientID: actor.GetClientID(),
+		}
+		if tgr.Actor.Subject == "" {
+			tgr.Actor.Subject = actor.GetClientID()
+		}
+		if dti, ok := subject.(oauth2.DelegatedTokenInfo); ok {
+			tgr.Actor.Actor = dti.GetActor()
+		}
+	}
+
+	tgr.UserID = subject.GetUserID()
+	if tgr.Scope == "" {
+		tgr.Scope = subject.GetScope()
+	}
+	tgr.Audience = append(tgr.Audience, tgr.Resource...)
+	// the exchanged token is issued to the audience of the subject token unless another one is requested
+	if len(tgr.Audience) == 0 {
+		tgr.Audience = tokenAudience(subject)
+	}
+
+	if fn := s.TokenExchangeHandler; fn != nil {
+		allowed, err := fn(ctx, tgr, subject, actor)
+		if err != nil {
+			return nil, err
+		} else if !allowed {
+			return nil, errors.ErrInvalidRequest
+		}
+	}
+
+	return s.Manager.GenerateAccessToken(ctx, oauth2.TokenExchange, tgr)
+}
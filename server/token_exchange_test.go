@@ -0,0 +1,210 @@
+package server_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/generates"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"github.com/go-oauth2/oauth2/v4/store"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestTokenExchange(t *testing.T) {
+	tsrv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testServer(t, w, r)
+	}))
+	defer tsrv.Close()
+	e := httpexpect.New(t, tsrv.URL)
+
+	cs := store.NewClientStore()
+	cs.Set(clientID, &models.Client{ID: clientID, Secret: clientSecret})
+	cs.Set("222222", &models.Client{ID: "222222", Secret: "22222222"})
+
+	mgr := manage.NewDefaultManager()
+	mgr.MustTokenStorage(store.NewMemoryTokenStore())
+	mgr.MapClientStorage(cs)
+	mgr.MapAccessGenerate(generates.NewJWTAccessGenerate("", []byte("00000000"), jwt.SigningMethodHS256))
+
+	cfg := server.NewConfig()
+	cfg.AllowedGrantTypes = append(cfg.AllowedGrantTypes, oauth2.TokenExchange)
+	srv = server.NewServer(cfg, mgr)
+	srv.SetPasswordAuthorizationHandler(func(ctx context.Context, clientID, username, password string) (string, error) {
+		return "000000", nil
+	})
+
+	subject := e.POST("/token").
+		WithFormField("grant_type", "password").
+		WithFormField("username", "admin").
+		WithFormField("password", "123456").
+		WithFormField("scope", "read write").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("access_token").String().Raw()
+
+	actor := e.POST("/token").
+		WithFormField("grant_type", "client_credentials").
+		WithBasicAuth("222222", "22222222").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("access_token").String().Raw()
+
+	e.POST("/token").
+		WithFormField("grant_type", string(oauth2.TokenExchange)).
+		WithFormField("subject_token", subject).
+		WithFormField("subject_token_type", oauth2.AccessTokenType).
+		WithFormField("scope", "admin").
+		WithBasicAuth("222222", "22222222").
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Equal("invalid_scope")
+
+	e.POST("/token").
+		WithFormField("grant_type", string(oauth2.TokenExchange)).
+		WithFormField("subject_token", "invalid").
+		WithFormField("subject_token_type", oauth2.AccessTokenType).
+		WithBasicAuth("222222", "22222222").
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Equal("invalid_request")
+
+	resObj := e.POST("/token").
+		WithFormField("grant_type", string(oauth2.TokenExchange)).
+		WithFormField("subject_token", subject).
+		WithFormField("subject_token_type", oauth2.AccessTokenType).
+		WithFormField("actor_token", actor).
+		WithFormField("actor_token_type", oauth2.AccessTokenType).
+		WithFormField("audience", "https://api.example.com").
+		WithFormField("scope", "read").
+		WithBasicAuth("222222", "22222222").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	resObj.Value("issued_token_type").Equal(oauth2.AccessTokenType)
+	resObj.Value("scope").Equal("read")
+	resObj.NotContainsKey("refresh_token")
+
+	claims := &generates.JWTAccessClaims{}
+	_, err := jwt.ParseWithClaims(resObj.Value("access_token").String().Raw(), claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte("00000000"), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Subject != "000000" || len(claims.Audience) != 1 || claims.Audience[0] != "https://api.example.com" {
+		t.Errorf("unexpected exchanged token claims: %+v", claims)
+	}
+	if claims.Actor == nil || claims.Actor.Subject != "222222" || claims.Actor.ClientID != "222222" {
+		t.Errorf("unexpected act claim: %+v", claims.Actor)
+	}
+
+	billing := e.POST("/token").
+		WithFormField("grant_type", "password").
+		WithFormField("username", "admin").
+		WithFormField("password", "123456").
+		WithFormField("resource", "https://billing.example.com").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("access_token").String().Raw()
+
+	// the token of the billing api can't be exchanged for the token of the admin api
+	e.POST("/token").
+		WithFormField("grant_type", string(oauth2.TokenExchange)).
+		WithFormField("subject_token", billing).
+		WithFormField("subject_token_type", oauth2.AccessTokenType).
+		WithFormField("audience", "https://admin.example.com").
+		WithBasicAuth("222222", "22222222").
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Equal("invalid_target")
+
+	access := e.POST("/token").
+		WithFormField("grant_type", string(oauth2.TokenExchange)).
+		WithFormField("subject_token", billing).
+		WithFormField("subject_token_type", oauth2.AccessTokenType).
+		WithBasicAuth("222222", "22222222").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("access_token").String().Raw()
+
+	claims = &generates.JWTAccessClaims{}
+	_, err = jwt.ParseWithClaims(access, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte("00000000"), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claims.Audience) != 1 || claims.Audience[0] != "https://billing.example.com" {
+		t.Errorf("unexpected exchanged token audience: %v", claims.Audience)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwk, err := models.NewJWK("", "ES256", key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof := func() string {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, &server.DPoPProofClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:       uuid.Must(uuid.NewRandom()).String(),
+				IssuedAt: jwt.NewNumericDate(time.Now()),
+			},
+			HTM: "POST",
+			HTU: tsrv.URL + "/token",
+		})
+		token.Header["typ"] = "dpop+jwt"
+		token.Header["jwk"] = jwk
+		v, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	bound := e.POST("/token").
+		WithHeader("DPoP", proof()).
+		WithFormField("grant_type", "password").
+		WithFormField("username", "admin").
+		WithFormField("password", "123456").
+		WithFormField("scope", "read").
+		WithBasicAuth(clientID, clientSecret).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("access_token").String().Raw()
+
+	// the DPoP-bound subject token can't be exchanged without the proof of its key
+	e.POST("/token").
+		WithFormField("grant_type", string(oauth2.TokenExchange)).
+		WithFormField("subject_token", bound).
+		WithFormField("subject_token_type", oauth2.AccessTokenType).
+		WithBasicAuth("222222", "22222222").
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Equal("invalid_request")
+
+	e.POST("/token").
+		WithHeader("DPoP", proof()).
+		WithFormField("grant_type", string(oauth2.TokenExchange)).
+		WithFormField("subject_token", bound).
+		WithFormField("subject_token_type", oauth2.AccessTokenType).
+		WithBasicAuth("222222", "22222222").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("token_type").Equal("DPoP")
+}
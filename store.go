@@ -1,6 +1,9 @@
 package oauth2
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type (
 	// ClientStore the client information storage interface
@@ -9,6 +12,21 @@ type (
 		GetByID(ctx context.Context, id string) (ClientInfo, error)
 	}
 
+	// ClientRegistrationStore the writable client information storage interface
+	// of the dynamic client registration
+	ClientRegistrationStore interface {
+		ClientStore
+
+		// create and store the new client information
+		Create(ctx context.Context, info ClientInfo) error
+
+		// replace the stored client information
+		Update(ctx context.Context, info ClientInfo) error
+
+		// delete the client information
+		RemoveByID(ctx context.Context, id string) error
+	}
+
 	// TokenStore the token information storage interface
 	TokenStore interface {
 		// create and store the new token information
@@ -32,4 +50,75 @@ type (
 		// use the refresh token for token information data
 		GetByRefresh(ctx context.Context, refresh string) (TokenInfo, error)
 	}
+
+	// DeviceCodeStore the device authorization information storage interface
+	DeviceCodeStore interface {
+		// create and store the new device authorization information,
+		// errors.ErrDuplicateUserCode is returned when the user code is already in use
+		Create(ctx context.Context, info DeviceCodeInfo) error
+
+		// update the stored device authorization information
+		Update(ctx context.Context, info DeviceCodeInfo) error
+
+		// use the device code to delete the device authorization information
+		RemoveByDeviceCode(ctx context.Context, deviceCode string) error
+
+		// use the device code for device authorization information data
+		GetByDeviceCode(ctx context.Context, deviceCode string) (DeviceCodeInfo, error)
+
+		// use the user code for device authorization information data
+		GetByUserCode(ctx context.Context, userCode string) (DeviceCodeInfo, error)
+	}
+
+	// PushedRequestStore the pushed authorization request storage interface
+	PushedRequestStore interface {
+		// create and store the new pushed authorization request
+		Create(ctx context.Context, info PushedRequestInfo) error
+
+		// use the request uri to delete the pushed authorization request
+		RemoveByURI(ctx context.Context, requestURI string) error
+
+		// use the request uri for pushed authorization request data
+		GetByURI(ctx context.Context, requestURI string) (PushedRequestInfo, error)
+	}
+
+	// TokenFamilyStore the token family storage interface, the tokens issued from the same
+	// authorization code or by rotating the refresh token belong to the family of the first token
+	TokenFamilyStore interface {
+		// update the current access and refresh token of the family until the expiration time
+		SetCurrent(ctx context.Context, family, access, refresh string, exp time.Time) error
+
+		// get the current access and refresh token of the family
+		GetCurrent(ctx context.Context, family string) (access, refresh string, err error)
+
+		// mark the redeemed authorization code or the rotated refresh token of the family as retired until the expiration time
+		Retire(ctx context.Context, family, token string, exp time.Time) error
+
+		// get the family of the retired token and the time it was retired, the family is empty if it isn't retired
+		GetRetired(ctx context.Context, token string) (family string, retiredAt time.Time, err error)
+
+		// delete the family, its retired tokens are kept to detect their reuse
+		RemoveFamily(ctx context.Context, family string) error
+	}
+
+	// ConsentStore the consent information storage interface
+	ConsentStore interface {
+		// create or replace the consent of the user to the client
+		Save(ctx context.Context, info ConsentInfo) error
+
+		// delete the consent of the user to the client
+		Remove(ctx context.Context, userID, clientID string) error
+
+		// get the consent of the user to the client
+		Get(ctx context.Context, userID, clientID string) (ConsentInfo, error)
+
+		// get the consents of the user to all clients
+		GetByUserID(ctx context.Context, userID string) ([]ConsentInfo, error)
+	}
+
+	// JTIStore the used jwt id storage interface, to detect the replayed jwt
+	JTIStore interface {
+		// mark the jwt id as used until the expiration time, fresh is false if it was used before
+		Use(ctx context.Context, jti string, exp time.Time) (fresh bool, err error)
+	}
 )
@@ -40,3 +40,36 @@ func (cs *ClientStore) Set(id string, cli oauth2.ClientInfo) (err error) {
 	cs.data[id] = cli
 	return
 }
+
+// Create create and store the new client information
+func (cs *ClientStore) Create(ctx context.Context, info oauth2.ClientInfo) error {
+	cs.Lock()
+	defer cs.Unlock()
+
+	if _, ok := cs.data[info.GetID()]; ok {
+		return errors.New("already exists")
+	}
+	cs.data[info.GetID()] = info
+	return nil
+}
+
+// Update replace the stored client information
+func (cs *ClientStore) Update(ctx context.Context, info oauth2.ClientInfo) error {
+	cs.Lock()
+	defer cs.Unlock()
+
+	if _, ok := cs.data[info.GetID()]; !ok {
+		return errors.New("not found")
+	}
+	cs.data[info.GetID()] = info
+	return nil
+}
+
+// RemoveByID delete the client information
+func (cs *ClientStore) RemoveByID(ctx context.Context, id string) error {
+	cs.Lock()
+	defer cs.Unlock()
+
+	delete(cs.data, id)
+	return nil
+}
@@ -20,5 +20,27 @@ func TestClientStore(t *testing.T) {
 		cli, err := clientStore.GetByID(context.Background(), "1")
 		So(err, ShouldBeNil)
 		So(cli.GetID(), ShouldEqual, "1")
+
+		Convey("Test registration", func() {
+			err := clientStore.Create(context.Background(), &models.Client{ID: "1"})
+			So(err, ShouldNotBeNil)
+
+			err = clientStore.Create(context.Background(), &models.Client{ID: "2", Secret: "3"})
+			So(err, ShouldBeNil)
+
+			err = clientStore.Update(context.Background(), &models.Client{ID: "2", Secret: "4"})
+			So(err, ShouldBeNil)
+			cli, err := clientStore.GetByID(context.Background(), "2")
+			So(err, ShouldBeNil)
+			So(cli.GetSecret(), ShouldEqual, "4")
+
+			err = clientStore.Update(context.Background(), &models.Client{ID: "5"})
+			So(err, ShouldNotBeNil)
+
+			err = clientStore.RemoveByID(context.Background(), "2")
+			So(err, ShouldBeNil)
+			_, err = clientStore.GetByID(context.Background(), "2")
+			So(err, ShouldNotBeNil)
+		})
 	})
 }
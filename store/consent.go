@@ -0,0 +1,125 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/tidwall/buntdb"
+)
+
+// NewMemoryConsentStore create a consent store instance based on memory
+func NewMemoryConsentStore() (oauth2.ConsentStore, error) {
+	return NewFileConsentStore(":memory:")
+}
+
+// NewFileConsentStore create a consent store instance based on file
+func NewFileConsentStore(filename string) (oauth2.ConsentStore, error) {
+	db, err := buntdb.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	// the consents of the user are listed by the index of the user id
+	if err := db.CreateIndex("user", "*", buntdb.IndexJSONCaseSensitive("UserID")); err != nil {
+		return nil, err
+	}
+	return &ConsentStore{db: db}, nil
+}
+
+// ConsentStore consent storage based on buntdb(https://github.com/tidwall/buntdb)
+type ConsentStore struct {
+	db *buntdb.DB
+}
+
+// the key of the consent of the user to the client
+func consentKey(userID, clientID string) string {
+	jv, _ := json.Marshal([]string{userID, clientID})
+	return string(jv)
+}
+
+// Save create or replace the consent of the user to the client
+func (cs *ConsentStore) Save(ctx context.Context, info oauth2.ConsentInfo) error {
+	jv, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	var exp time.Time
+	if v := info.GetExpiresIn(); v > 0 {
+		exp = info.GetCreateAt().Add(v)
+	}
+
+	return cs.db.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(consentKey(info.GetUserID(), info.GetClientID()), string(jv), expireOptions(exp))
+		return err
+	})
+}
+
+// Remove delete the consent of the user to the client
+func (cs *ConsentStore) Remove(ctx context.Context, userID, clientID string) error {
+	err := cs.db.Update(func(tx *buntdb.Tx) error {
+		_, err := tx.Delete(consentKey(userID, clientID))
+		return err
+	})
+	if err == buntdb.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+// Get get the consent of the user to the client
+func (cs *ConsentStore) Get(ctx context.Context, userID, clientID string) (oauth2.ConsentInfo, error) {
+	var ci oauth2.ConsentInfo
+	err := cs.db.View(func(tx *buntdb.Tx) error {
+		jv, err := tx.Get(consentKey(userID, clientID))
+		if err != nil {
+			return err
+		}
+
+		var cm models.Consent
+		err = json.Unmarshal([]byte(jv), &cm)
+		if err != nil {
+			return err
+		}
+		ci = &cm
+		return nil
+	})
+	if err != nil {
+		if err == buntdb.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ci, nil
+}
+
+// GetByUserID get the consents of the user to all clients
+func (cs *ConsentStore) GetByUserID(ctx context.Context, userID string) ([]oauth2.ConsentInfo, error) {
+	pivot, err := json.Marshal(map[string]string{"UserID": userID})
+	if err != nil {
+		return nil, err
+	}
+
+	var cis []oauth2.ConsentInfo
+	err = cs.db.View(func(tx *buntdb.Tx) error {
+		var uerr error
+		err := tx.AscendEqual("user", string(pivot), func(key, value string) bool {
+			var cm models.Consent
+			if uerr = json.Unmarshal([]byte(value), &cm); uerr != nil {
+				return false
+			}
+			cis = append(cis, &cm)
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		return uerr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cis, nil
+}
@@ -0,0 +1,64 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/go-oauth2/oauth2/v4/store"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestConsentStore(t *testing.T) {
+	Convey("Test consent store", t, func() {
+		ctx := context.Background()
+		cstore, err := store.NewMemoryConsentStore()
+		So(err, ShouldBeNil)
+
+		err = cstore.Save(ctx, &models.Consent{
+			UserID:   "user_1",
+			ClientID: "1",
+			Scope:    "read",
+			CreateAt: time.Now(),
+		})
+		So(err, ShouldBeNil)
+		err = cstore.Save(ctx, &models.Consent{
+			UserID:    "user_1",
+			ClientID:  "2",
+			Scope:     "read write",
+			CreateAt:  time.Now(),
+			ExpiresIn: time.Second * 5,
+		})
+		So(err, ShouldBeNil)
+		err = cstore.Save(ctx, &models.Consent{
+			UserID:   "user_2",
+			ClientID: "1",
+			Scope:    "write",
+			CreateAt: time.Now(),
+		})
+		So(err, ShouldBeNil)
+
+		ci, err := cstore.Get(ctx, "user_1", "2")
+		So(err, ShouldBeNil)
+		So(ci.GetScope(), ShouldEqual, "read write")
+		So(ci.GetExpiresIn(), ShouldEqual, time.Second*5)
+
+		cis, err := cstore.GetByUserID(ctx, "user_1")
+		So(err, ShouldBeNil)
+		So(cis, ShouldHaveLength, 2)
+
+		err = cstore.Remove(ctx, "user_1", "2")
+		So(err, ShouldBeNil)
+
+		ci, err = cstore.Get(ctx, "user_1", "2")
+		So(err, ShouldBeNil)
+		So(ci, ShouldBeNil)
+
+		cis, err = cstore.GetByUserID(ctx, "user_1")
+		So(err, ShouldBeNil)
+		So(cis, ShouldHaveLength, 1)
+		So(cis[0].GetClientID(), ShouldEqual, "1")
+	})
+}
@@ -0,0 +1,140 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/tidwall/buntdb"
+)
+
+// the key prefix of the user code index
+const userCodePrefix = "user_code:"
+
+// NewMemoryDeviceCodeStore create a device authorization store instance based on memory
+func NewMemoryDeviceCodeStore() (oauth2.DeviceCodeStore, error) {
+	return NewFileDeviceCodeStore(":memory:")
+}
+
+// NewFileDeviceCodeStore create a device authorization store instance based on file
+func NewFileDeviceCodeStore(filename string) (oauth2.DeviceCodeStore, error) {
+	db, err := buntdb.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &DeviceCodeStore{db: db}, nil
+}
+
+// DeviceCodeStore device authorization storage based on buntdb(https://github.com/tidwall/buntdb)
+type DeviceCodeStore struct {
+	db *buntdb.DB
+}
+
+// set the device authorization information until the device code expires,
+// the new device authorization can't take the user code of another pending one
+func (ds *DeviceCodeStore) set(info oauth2.DeviceCodeInfo, create bool) error {
+	jv, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Until(info.GetCreateAt().Add(info.GetExpiresIn()))
+	if ttl <= 0 {
+		ttl = time.Millisecond
+	}
+	opts := &buntdb.SetOptions{Expires: true, TTL: ttl}
+
+	return ds.db.Update(func(tx *buntdb.Tx) error {
+		userCodeKey := userCodePrefix + info.GetUserCode()
+		if create {
+			if _, err := tx.Get(userCodeKey); err == nil {
+				return errors.ErrDuplicateUserCode
+			} else if err != buntdb.ErrNotFound {
+				return err
+			}
+		}
+
+		_, _, err := tx.Set(info.GetDeviceCode(), string(jv), opts)
+		if err != nil {
+			return err
+		}
+		_, _, err = tx.Set(userCodeKey, info.GetDeviceCode(), opts)
+		return err
+	})
+}
+
+// Create create and store the new device authorization information
+func (ds *DeviceCodeStore) Create(ctx context.Context, info oauth2.DeviceCodeInfo) error {
+	return ds.set(info, true)
+}
+
+// Update update the stored device authorization information
+func (ds *DeviceCodeStore) Update(ctx context.Context, info oauth2.DeviceCodeInfo) error {
+	return ds.set(info, false)
+}
+
+// RemoveByDeviceCode use the device code to delete the device authorization information
+func (ds *DeviceCodeStore) RemoveByDeviceCode(ctx context.Context, deviceCode string) error {
+	info, err := ds.GetByDeviceCode(ctx, deviceCode)
+	if err != nil || info == nil {
+		return err
+	}
+
+	return ds.db.Update(func(tx *buntdb.Tx) error {
+		for _, key := range []string{deviceCode, userCodePrefix + info.GetUserCode()} {
+			if _, err := tx.Delete(key); err != nil && err != buntdb.ErrNotFound {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetByDeviceCode use the device code for device authorization information data
+func (ds *DeviceCodeStore) GetByDeviceCode(ctx context.Context, deviceCode string) (oauth2.DeviceCodeInfo, error) {
+	var di oauth2.DeviceCodeInfo
+	err := ds.db.View(func(tx *buntdb.Tx) error {
+		jv, err := tx.Get(deviceCode)
+		if err != nil {
+			return err
+		}
+
+		var dm models.DeviceCode
+		err = json.Unmarshal([]byte(jv), &dm)
+		if err != nil {
+			return err
+		}
+		di = &dm
+		return nil
+	})
+	if err != nil {
+		if err == buntdb.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return di, nil
+}
+
+// GetByUserCode use the user code for device authorization information data
+func (ds *DeviceCodeStore) GetByUserCode(ctx context.Context, userCode string) (oauth2.DeviceCodeInfo, error) {
+	var deviceCode string
+	err := ds.db.View(func(tx *buntdb.Tx) error {
+		v, err := tx.Get(userCodePrefix + userCode)
+		if err != nil {
+			return err
+		}
+		deviceCode = v
+		return nil
+	})
+	if err != nil {
+		if err == buntdb.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ds.GetByDeviceCode(ctx, deviceCode)
+}
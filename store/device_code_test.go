@@ -0,0 +1,70 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/go-oauth2/oauth2/v4/store"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDeviceCodeStore(t *testing.T) {
+	Convey("Test device code store", t, func() {
+		ctx := context.Background()
+		dstore, err := store.NewMemoryDeviceCodeStore()
+		So(err, ShouldBeNil)
+
+		info := &models.DeviceCode{
+			ClientID:   "1",
+			Scope:      "all",
+			DeviceCode: "11_11_11",
+			UserCode:   "BCDFGHJK",
+			CreateAt:   time.Now(),
+			ExpiresIn:  time.Second * 5,
+			Interval:   time.Second,
+			Status:     oauth2.DeviceCodePending,
+		}
+		err = dstore.Create(ctx, info)
+		So(err, ShouldBeNil)
+
+		// the user code of the pending device authorization can't be taken
+		err = dstore.Create(ctx, &models.DeviceCode{
+			ClientID:   "2",
+			DeviceCode: "22_22_22",
+			UserCode:   info.UserCode,
+			CreateAt:   time.Now(),
+			ExpiresIn:  time.Second * 5,
+			Status:     oauth2.DeviceCodePending,
+		})
+		So(err, ShouldEqual, errors.ErrDuplicateUserCode)
+
+		dinfo, err := dstore.GetByUserCode(ctx, info.UserCode)
+		So(err, ShouldBeNil)
+		So(dinfo.GetDeviceCode(), ShouldEqual, info.DeviceCode)
+
+		info.UserID = "1_1"
+		info.Status = oauth2.DeviceCodeApproved
+		err = dstore.Update(ctx, info)
+		So(err, ShouldBeNil)
+
+		dinfo, err = dstore.GetByDeviceCode(ctx, info.DeviceCode)
+		So(err, ShouldBeNil)
+		So(dinfo.GetUserID(), ShouldEqual, "1_1")
+		So(dinfo.GetStatus(), ShouldEqual, oauth2.DeviceCodeApproved)
+
+		err = dstore.RemoveByDeviceCode(ctx, info.DeviceCode)
+		So(err, ShouldBeNil)
+
+		dinfo, err = dstore.GetByDeviceCode(ctx, info.DeviceCode)
+		So(err, ShouldBeNil)
+		So(dinfo, ShouldBeNil)
+		dinfo, err = dstore.GetByUserCode(ctx, info.UserCode)
+		So(err, ShouldBeNil)
+		So(dinfo, ShouldBeNil)
+	})
+}
@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/tidwall/buntdb"
+)
+
+// NewMemoryJTIStore create a jwt id store instance based on memory
+func NewMemoryJTIStore() (oauth2.JTIStore, error) {
+	return NewFileJTIStore(":memory:")
+}
+
+// NewFileJTIStore create a jwt id store instance based on file
+func NewFileJTIStore(filename string) (oauth2.JTIStore, error) {
+	db, err := buntdb.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &JTIStore{db: db}, nil
+}
+
+// JTIStore used jwt id storage based on buntdb(https://github.com/tidwall/buntdb)
+type JTIStore struct {
+	db *buntdb.DB
+}
+
+// Use mark the jwt id as used until the expiration time, fresh is false if it was used before
+func (js *JTIStore) Use(ctx context.Context, jti string, exp time.Time) (bool, error) {
+	fresh := false
+	err := js.db.Update(func(tx *buntdb.Tx) error {
+		_, err := tx.Get(jti)
+		if err == nil {
+			return nil
+		} else if err != buntdb.ErrNotFound {
+			return err
+		}
+
+		ttl := time.Until(exp)
+		if ttl <= 0 {
+			ttl = time.Millisecond
+		}
+		fresh = true
+		_, _, err = tx.Set(jti, exp.Format(time.RFC3339), &buntdb.SetOptions{Expires: true, TTL: ttl})
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return fresh, nil
+}
@@ -0,0 +1,31 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4/store"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestJTIStore(t *testing.T) {
+	Convey("Test jwt id store", t, func() {
+		ctx := context.Background()
+		jstore, err := store.NewMemoryJTIStore()
+		So(err, ShouldBeNil)
+
+		fresh, err := jstore.Use(ctx, "jti_1", time.Now().Add(time.Second))
+		So(err, ShouldBeNil)
+		So(fresh, ShouldBeTrue)
+
+		fresh, err = jstore.Use(ctx, "jti_1", time.Now().Add(time.Second))
+		So(err, ShouldBeNil)
+		So(fresh, ShouldBeFalse)
+
+		fresh, err = jstore.Use(ctx, "jti_2", time.Now().Add(time.Second))
+		So(err, ShouldBeNil)
+		So(fresh, ShouldBeTrue)
+	})
+}
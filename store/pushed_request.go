@@ -0,0 +1,86 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/tidwall/buntdb"
+)
+
+// NewMemoryPushedRequestStore create a pushed authorization request store instance based on memory
+func NewMemoryPushedRequestStore() (oauth2.PushedRequestStore, error) {
+	return NewFilePushedRequestStore(":memory:")
+}
+
+// NewFilePushedRequestStore create a pushed authorization request store instance based on file
+func NewFilePushedRequestStore(filename string) (oauth2.PushedRequestStore, error) {
+	db, err := buntdb.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &PushedRequestStore{db: db}, nil
+}
+
+// PushedRequestStore pushed authorization request storage based on buntdb(https://github.com/tidwall/buntdb)
+type PushedRequestStore struct {
+	db *buntdb.DB
+}
+
+// Create create and store the new pushed authorization request
+func (ps *PushedRequestStore) Create(ctx context.Context, info oauth2.PushedRequestInfo) error {
+	jv, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Until(info.GetCreateAt().Add(info.GetExpiresIn()))
+	if ttl <= 0 {
+		ttl = time.Millisecond
+	}
+
+	return ps.db.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(info.GetRequestURI(), string(jv), &buntdb.SetOptions{Expires: true, TTL: ttl})
+		return err
+	})
+}
+
+// RemoveByURI use the request uri to delete the pushed authorization request
+func (ps *PushedRequestStore) RemoveByURI(ctx context.Context, requestURI string) error {
+	err := ps.db.Update(func(tx *buntdb.Tx) error {
+		_, err := tx.Delete(requestURI)
+		return err
+	})
+	if err == buntdb.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+// GetByURI use the request uri for pushed authorization request data
+func (ps *PushedRequestStore) GetByURI(ctx context.Context, requestURI string) (oauth2.PushedRequestInfo, error) {
+	var pi oauth2.PushedRequestInfo
+	err := ps.db.View(func(tx *buntdb.Tx) error {
+		jv, err := tx.Get(requestURI)
+		if err != nil {
+			return err
+		}
+
+		var pm models.PushedRequest
+		err = json.Unmarshal([]byte(jv), &pm)
+		if err != nil {
+			return err
+		}
+		pi = &pm
+		return nil
+	})
+	if err != nil {
+		if err == buntdb.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return pi, nil
+}
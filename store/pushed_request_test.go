@@ -0,0 +1,43 @@
+package store_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/go-oauth2/oauth2/v4/store"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPushedRequestStore(t *testing.T) {
+	Convey("Test pushed authorization request store", t, func() {
+		ctx := context.Background()
+		pstore, err := store.NewMemoryPushedRequestStore()
+		So(err, ShouldBeNil)
+
+		info := &models.PushedRequest{
+			RequestURI: "urn:ietf:params:oauth:request_uri:11_11_11",
+			ClientID:   "1",
+			Params:     url.Values{"response_type": {"code"}, "scope": {"all"}},
+			CreateAt:   time.Now(),
+			ExpiresIn:  time.Second * 5,
+		}
+		err = pstore.Create(ctx, info)
+		So(err, ShouldBeNil)
+
+		pinfo, err := pstore.GetByURI(ctx, info.RequestURI)
+		So(err, ShouldBeNil)
+		So(pinfo.GetClientID(), ShouldEqual, "1")
+		So(pinfo.GetParams().Get("scope"), ShouldEqual, "all")
+
+		err = pstore.RemoveByURI(ctx, info.RequestURI)
+		So(err, ShouldBeNil)
+
+		pinfo, err = pstore.GetByURI(ctx, info.RequestURI)
+		So(err, ShouldBeNil)
+		So(pinfo, ShouldBeNil)
+	})
+}
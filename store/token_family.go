@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/tidwall/buntdb"
+)
+
+// NewMemoryTokenFamilyStore create a token family store instance based on memory
+func NewMemoryTokenFamilyStore() (oauth2.TokenFamilyStore, error) {
+	return NewFileTokenFamilyStore(":memory:")
+}
+
+// NewFileTokenFamilyStore create a token family store instance based on file
+func NewFileTokenFamilyStore(filename string) (oauth2.TokenFamilyStore, error) {
+	db, err := buntdb.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenFamilyStore{db: db}, nil
+}
+
+// TokenFamilyStore token family storage based on buntdb(https://github.com/tidwall/buntdb)
+type TokenFamilyStore struct {
+	db *buntdb.DB
+}
+
+type familyCurrent struct {
+	Access  string `json:"access"`
+	Refresh string `json:"refresh"`
+}
+
+type familyRetired struct {
+	Family    string    `json:"family"`
+	RetiredAt time.Time `json:"retired_at"`
+}
+
+// the options of the key expiring at the time, the zero time never expires
+func expireOptions(exp time.Time) *buntdb.SetOptions {
+	if exp.IsZero() {
+		return nil
+	}
+	ttl := time.Until(exp)
+	if ttl <= 0 {
+		ttl = time.Millisecond
+	}
+	return &buntdb.SetOptions{Expires: true, TTL: ttl}
+}
+
+func (fs *TokenFamilyStore) set(key string, v interface{}, exp time.Time) error {
+	jv, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return fs.db.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(key, string(jv), expireOptions(exp))
+		return err
+	})
+}
+
+func (fs *TokenFamilyStore) get(key string, v interface{}) (bool, error) {
+	var jv string
+	err := fs.db.View(func(tx *buntdb.Tx) error {
+		var err error
+		jv, err = tx.Get(key)
+		return err
+	})
+	if err == buntdb.ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal([]byte(jv), v)
+}
+
+// SetCurrent update the current access and refresh token of the family until the expiration time
+func (fs *TokenFamilyStore) SetCurrent(ctx context.Context, family, access, refresh string, exp time.Time) error {
+	return fs.set("family:"+family, &familyCurrent{Access: access, Refresh: refresh}, exp)
+}
+
+// GetCurrent get the current access and refresh token of the family
+func (fs *TokenFamilyStore) GetCurrent(ctx context.Context, family string) (string, string, error) {
+	var fc familyCurrent
+	if _, err := fs.get("family:"+family, &fc); err != nil {
+		return "", "", err
+	}
+	return fc.Access, fc.Refresh, nil
+}
+
+// Retire mark the redeemed authorization code or the rotated refresh token of the family as retired until the expiration time
+func (fs *TokenFamilyStore) Retire(ctx context.Context, family, token string, exp time.Time) error {
+	return fs.set("retired:"+token, &familyRetired{Family: family, RetiredAt: time.Now()}, exp)
+}
+
+// GetRetired get the family of the retired token and the time it was retired, the family is empty if it isn't retired
+func (fs *TokenFamilyStore) GetRetired(ctx context.Context, token string) (string, time.Time, error) {
+	var fr familyRetired
+	if _, err := fs.get("retired:"+token, &fr); err != nil {
+		return "", time.Time{}, err
+	}
+	return fr.Family, fr.RetiredAt, nil
+}
+
+// RemoveFamily delete the family, its retired tokens are kept to detect their reuse
+func (fs *TokenFamilyStore) RemoveFamily(ctx context.Context, family string) error {
+	err := fs.db.Update(func(tx *buntdb.Tx) error {
+		_, err := tx.Delete("family:" + family)
+		return err
+	})
+	if err == buntdb.ErrNotFound {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4/store"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTokenFamilyStore(t *testing.T) {
+	Convey("Test token family store", t, func() {
+		ctx := context.Background()
+		fstore, err := store.NewMemoryTokenFamilyStore()
+		So(err, ShouldBeNil)
+
+		err = fstore.SetCurrent(ctx, "family_1", "access_1", "refresh_1", time.Now().Add(time.Second*5))
+		So(err, ShouldBeNil)
+
+		access, refresh, err := fstore.GetCurrent(ctx, "family_1")
+		So(err, ShouldBeNil)
+		So(access, ShouldEqual, "access_1")
+		So(refresh, ShouldEqual, "refresh_1")
+
+		err = fstore.Retire(ctx, "family_1", "refresh_0", time.Time{})
+		So(err, ShouldBeNil)
+
+		family, retiredAt, err := fstore.GetRetired(ctx, "refresh_0")
+		So(err, ShouldBeNil)
+		So(family, ShouldEqual, "family_1")
+		So(retiredAt.IsZero(), ShouldBeFalse)
+
+		family, _, err = fstore.GetRetired(ctx, "refresh_1")
+		So(err, ShouldBeNil)
+		So(family, ShouldBeEmpty)
+
+		err = fstore.RemoveFamily(ctx, "family_1")
+		So(err, ShouldBeNil)
+
+		access, refresh, err = fstore.GetCurrent(ctx, "family_1")
+		So(err, ShouldBeNil)
+		So(access, ShouldBeEmpty)
+		So(refresh, ShouldBeEmpty)
+
+		family, _, err = fstore.GetRetired(ctx, "refresh_0")
+		So(err, ShouldBeNil)
+		So(family, ShouldEqual, "family_1")
+	})
+}